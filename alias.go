@@ -0,0 +1,107 @@
+package clino
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// aliases defined by users, mapping names to the arguments they expand to.
+type aliases map[string][]string
+
+// loadAliases reads the aliases from the alias file and the environment.
+// Aliases defined on the environment take precedence.
+func (p *Program) loadAliases() (aliases, error) {
+	a := aliases{}
+	if p.AliasFile != "" {
+		f, err := os.Open(p.AliasFile)
+		switch {
+		case os.IsNotExist(err):
+		case err != nil:
+			return nil, ConfigError(err)
+		default:
+			err = a.parse(f, p.AliasFile)
+			f.Close()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	env := p.envName("ALIASES")
+	if v := os.Getenv(env); v != "" {
+		if err := a.parse(strings.NewReader(strings.Replace(v, ";", "\n", -1)), "$"+env); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// parse "name = expansion" definitions, one per line.
+// Empty lines and lines starting with # are ignored.
+func (a aliases) parse(r io.Reader, source string) error {
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		def := strings.TrimSpace(s.Text())
+		if def == "" || strings.HasPrefix(def, "#") {
+			continue
+		}
+		if err := a.define(def); err != nil {
+			return ConfigError(fmt.Errorf("invalid alias on %s:%d: %w", source, line, err))
+		}
+	}
+	if err := s.Err(); err != nil {
+		return ConfigError(fmt.Errorf("cannot read aliases from %s: %w", source, err))
+	}
+	return nil
+}
+
+func (a aliases) define(def string) error {
+	i := strings.Index(def, "=")
+	if i == -1 {
+		return fmt.Errorf("missing '=' on %q", def)
+	}
+	name := strings.TrimSpace(def[:i])
+	if name == "" || strings.HasPrefix(name, "-") || strings.IndexFunc(name, unicode.IsSpace) != -1 {
+		return fmt.Errorf("invalid name %q", name)
+	}
+	expansion, err := splitWords(def[i+1:])
+	if err != nil {
+		return fmt.Errorf("alias %s: %w", name, err)
+	}
+	if len(expansion) == 0 {
+		return fmt.Errorf("alias %s is empty", name)
+	}
+	a[name] = expansion
+	return nil
+}
+
+// expandAliases replaces the first argument with the arguments of its alias, if any, recursively.
+// Aliases never shadow commands of the root command, nor the help command.
+func (p *Program) expandAliases(args []string) ([]string, error) {
+	a, err := p.loadAliases()
+	if err != nil || len(a) == 0 {
+		return args, err
+	}
+	var seen []string
+	for len(args) != 0 {
+		name := args[0]
+		expansion, ok := a[name]
+		if !ok || name == "help" {
+			return args, nil
+		}
+		if _, ok := p.lookupCommand([]Command{p.Root}, name); ok {
+			return args, nil
+		}
+		for _, s := range seen {
+			if s == name {
+				return nil, ConfigError(fmt.Errorf("alias loop: %s -> %s", strings.Join(seen, " -> "), name))
+			}
+		}
+		seen = append(seen, name)
+		args = append(expansion[:len(expansion):len(expansion)], args[1:]...)
+	}
+	return args, nil
+}
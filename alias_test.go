@@ -0,0 +1,148 @@
+package clino
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestUserAliases(t *testing.T) {
+	f, err := ioutil.TempFile("", "clino-aliases")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(`# aliases
+co = checkout -force
+msg = commit -m "quick fix"
+c = co
+checkout = commit
+`)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		desc     string
+		env      string
+		file     string
+		args     []string
+		wantCmd  string
+		wantArgs []string
+		wantErr  string
+		wantCode int
+	}{
+		{
+			desc:     "alias",
+			file:     f.Name(),
+			args:     []string{"co", "main"},
+			wantCmd:  "checkout",
+			wantArgs: []string{"main"},
+		},
+		{
+			desc:     "quoted",
+			file:     f.Name(),
+			args:     []string{"msg", "now"},
+			wantCmd:  "commit",
+			wantArgs: []string{"quick fix", "now"},
+		},
+		{
+			desc:     "alias of alias",
+			file:     f.Name(),
+			args:     []string{"c"},
+			wantCmd:  "checkout",
+			wantArgs: []string{},
+		},
+		{
+			desc:     "aliases don't shadow commands",
+			file:     f.Name(),
+			args:     []string{"checkout"},
+			wantCmd:  "checkout",
+			wantArgs: []string{},
+		},
+		{
+			desc:     "environment takes precedence",
+			file:     f.Name(),
+			env:      "co = commit; m = commit -m 'from env'",
+			args:     []string{"co"},
+			wantCmd:  "commit",
+			wantArgs: []string{},
+		},
+		{
+			desc:     "environment only",
+			env:      "m = commit -m 'from env'",
+			args:     []string{"m"},
+			wantCmd:  "commit",
+			wantArgs: []string{"from env"},
+		},
+		{
+			desc:     "missing file",
+			file:     f.Name() + "-missing",
+			args:     []string{"checkout"},
+			wantCmd:  "checkout",
+			wantArgs: []string{},
+		},
+		{
+			desc:     "loop",
+			env:      "a = b\nb = c\nc = a",
+			args:     []string{"a"},
+			wantErr:  "alias loop: a -> b -> c -> a",
+			wantCode: ExitConfig,
+		},
+		{
+			desc:     "invalid",
+			env:      "co = checkout\nbad = 'unterminated",
+			args:     []string{"co"},
+			wantErr:  "invalid alias on $APP_ALIASES:2: alias bad: unterminated quoted string",
+			wantCode: ExitConfig,
+		},
+		{
+			desc:     "missing equal sign",
+			env:      "co checkout",
+			args:     []string{"co"},
+			wantErr:  `invalid alias on $APP_ALIASES:1: missing '=' on "co checkout"`,
+			wantCode: ExitConfig,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			os.Setenv("APP_ALIASES", tc.env)
+			defer os.Unsetenv("APP_ALIASES")
+			checkout := &treeCommand{name: "checkout", flags: []string{"force"}}
+			commit := &treeCommand{name: "commit", flags: []string{"m"}}
+			root := &treeCommand{name: "app", children: []Command{checkout, commit}}
+			p := Program{
+				Root:        root,
+				Output:      ioutil.Discard,
+				UserAliases: true,
+				AliasFile:   tc.file,
+			}
+			err := p.Run(context.Background(), tc.args...)
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Errorf("wanted error %q, got %v instead", tc.wantErr, err)
+				}
+				if code := ExitCode(err); code != tc.wantCode {
+					t.Errorf("wanted exit code %d, got %d instead", tc.wantCode, code)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("wanted error to be nil, got %v instead", err)
+			}
+			trail := p.trail
+			if got := trail[len(trail)-1].Name(); got != tc.wantCmd {
+				t.Errorf("wanted command %q to run, got %q instead", tc.wantCmd, got)
+			}
+			cmd := trail[len(trail)-1].(*treeCommand)
+			if !reflect.DeepEqual(cmd.args, tc.wantArgs) {
+				t.Errorf("got arguments %q, wanted %q", cmd.args, tc.wantArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package clino
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// expandArgsFiles replaces arguments of the form @path before "--" with the arguments read from the file.
+// Arguments starting with @@ are unescaped to a literal argument starting with @ instead.
+func expandArgsFiles(args []string) ([]string, error) {
+	var expanded []string
+	for i, arg := range args {
+		if arg == "--" {
+			return append(expanded, args[i:]...), nil
+		}
+		if strings.HasPrefix(arg, "@@") {
+			expanded = append(expanded, arg[1:])
+			continue
+		}
+		if len(arg) < 2 || arg[0] != '@' {
+			expanded = append(expanded, arg)
+			continue
+		}
+		fargs, err := readArgsFile(arg[1:])
+		if err != nil {
+			return nil, err
+		}
+		expanded = append(expanded, fargs...)
+	}
+	return expanded, nil
+}
+
+// readArgsFile reads arguments from the file, split like a shell does.
+// Comments start with # and quoted values might span multiple lines.
+func readArgsFile(path string) ([]string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, NoInputError(fmt.Errorf("cannot read arguments: %w", err))
+	}
+	args, err := splitWordsComments(string(b))
+	if err != nil {
+		return nil, DataError(fmt.Errorf("invalid arguments on %s: %w", path, err))
+	}
+	return args, nil
+}
@@ -0,0 +1,118 @@
+package clino
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestArgsFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clino-argsfiles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"deploy":  "# deployment\ndeploy # command\n\n-force\n\"hello world\" 'x y'\n",
+		"nested":  "@deploy\n",
+		"invalid": "ok\n'unterminated\n",
+		"long":    "deploy " + strings.Repeat("-force ", 20000) + "\n",
+		"quoted":  "deploy 'first line\n# not a comment\nlast line'\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	at := func(name string) string {
+		return "@" + filepath.Join(dir, name)
+	}
+
+	testCases := []struct {
+		desc     string
+		disabled bool
+		args     []string
+		wantArgs []string
+		wantErr  string
+		wantCode int
+	}{
+		{
+			desc:     "expand",
+			args:     []string{at("deploy"), "last"},
+			wantArgs: []string{"hello world", "x y", "last"},
+		},
+		{
+			desc:     "not after --",
+			args:     []string{at("deploy"), "--", at("deploy"), "@"},
+			wantArgs: []string{"hello world", "x y", "--", at("deploy"), "@"},
+		},
+		{
+			desc:     "not nested",
+			args:     []string{"deploy", at("nested")},
+			wantArgs: []string{"@deploy"},
+		},
+		{
+			desc:     "long line",
+			args:     []string{at("long"), "last"},
+			wantArgs: []string{"last"},
+		},
+		{
+			desc:     "quoted value spanning lines",
+			args:     []string{at("quoted")},
+			wantArgs: []string{"first line\n# not a comment\nlast line"},
+		},
+		{
+			desc:     "escaped",
+			args:     []string{"deploy", "@@literal", "@@", "--", "@@after"},
+			wantArgs: []string{"@literal", "@", "--", "@@after"},
+		},
+		{
+			desc:     "disabled",
+			disabled: true,
+			args:     []string{"deploy", at("deploy")},
+			wantArgs: []string{at("deploy")},
+		},
+		{
+			desc:     "missing file",
+			args:     []string{at("missing")},
+			wantErr:  "cannot read arguments: open " + filepath.Join(dir, "missing") + ": ",
+			wantCode: ExitNoInput,
+		},
+		{
+			desc:     "invalid",
+			args:     []string{at("invalid")},
+			wantErr:  "invalid arguments on " + filepath.Join(dir, "invalid") + ": unterminated quoted string",
+			wantCode: ExitDataErr,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			deploy := &treeCommand{name: "deploy", flags: []string{"force"}}
+			p := Program{
+				Root:      &defaultParentCommand{name: "app", children: []Command{deploy}},
+				Output:    ioutil.Discard,
+				ArgsFiles: !tc.disabled,
+			}
+			err := p.Run(context.Background(), tc.args...)
+			if tc.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tc.wantErr) {
+					t.Errorf("wanted error %q, got %v instead", tc.wantErr, err)
+				}
+				if code := ExitCode(err); code != tc.wantCode {
+					t.Errorf("wanted exit code %d, got %d instead", tc.wantCode, code)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("wanted error to be nil, got %v instead", err)
+			}
+			if !reflect.DeepEqual(deploy.args, tc.wantArgs) {
+				t.Errorf("got arguments %q, wanted %q", deploy.args, tc.wantArgs)
+			}
+		})
+	}
+}
@@ -21,7 +21,9 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"reflect"
 	"strings"
+	"time"
 )
 
 // Command contains the minimal interface for a command: its name (usage).
@@ -45,6 +47,37 @@ type Runnable interface {
 	Run(ctx context.Context, args ...string) error
 }
 
+// PreRunner commands run the PreRun function before Run, after parsing any flags.
+// If PreRun returns an error, Run is not called.
+type PreRunner interface {
+	PreRun(ctx context.Context, args ...string) error
+}
+
+// PostRunner commands run the PostRun function after Run, even if Run returns an error.
+// It is not called if the PreRun function of the command returns an error.
+type PostRunner interface {
+	PostRun(ctx context.Context, args ...string) error
+}
+
+// PersistentPreRunner is similar to PreRunner, but it is also called when running any of the command's offspring.
+// The PersistentPreRun functions are called from the root command to the command being run, before any PreRun.
+// 	// PersistentPreRun of the "main" command.
+// 	func (mc *MainCommand) PersistentPreRun(ctx context.Context, args ...string) (err error) {
+//		mc.db, err = sql.Open("postgres", mc.dsn)
+//		return err
+// 	}
+type PersistentPreRunner interface {
+	PersistentPreRun(ctx context.Context, args ...string) error
+}
+
+// PersistentPostRunner is similar to PostRunner, but it is also called when running any of the command's offspring.
+// The PersistentPostRun functions are called from the command being run to the root command, after any PostRun.
+// It is called even if Run returns an error, unless the PersistentPreRun function of the same command fails
+// (or of one of its ancestors).
+type PersistentPostRunner interface {
+	PersistentPostRun(ctx context.Context, args ...string) error
+}
+
 // FlagSet you want to use on your command.
 // 	// Flags of the "hello" command.
 // 	func (hc *HelloCommand) Flags(flags *flag.FlagSet) {
@@ -82,6 +115,30 @@ type Parent interface {
 	Commands() []Command
 }
 
+// Lookuper can be implemented by a parent command to find a subcommand by name without creating all of them.
+//
+// It is only used when Program.LazyCommands is set.
+// Lookup should return nil if there is no subcommand with the given name.
+type Lookuper interface {
+	Lookup(name string) Command
+}
+
+// DefaultCommander can be implemented by a parent command that isn't runnable
+// to run one of its subcommands when invoked without one, such as "app config" running "app config show".
+// The arguments are forwarded to the default command.
+//
+// If the first argument isn't a flag, it is considered a mistyped subcommand, and Run fails with an ExitUsage error,
+// unless the default command implements the ArgsAccepter interface.
+type DefaultCommander interface {
+	DefaultCommand() string
+}
+
+// ArgsAccepter can be implemented by a default command (see DefaultCommander) to accept positional arguments,
+// such as "app config name" running "app config show name".
+type ArgsAccepter interface {
+	AcceptsArgs() bool
+}
+
 // Program you want to run.
 //
 // You should call the Run function, passing the context, root command, and process arguments.
@@ -94,15 +151,166 @@ type Program struct {
 	// Deprecated: Use PersistentFlags instead.
 	GlobalFlags func(flags *flag.FlagSet)
 
+	// Input of the application.
+	//
+	// If not set when calling Run, os.Stdin is set.
+	// You probably only want to set this for testing.
+	Input io.Reader
+
 	// Output is the default output function to the application.
 	//
 	// If not set when calling Run, os.Stdout is set.
 	// You probably only want to set this for testing.
 	Output io.Writer
 
-	fs *flag.FlagSet
+	// ErrOutput is the error output of the application.
+	//
+	// If not set when calling Run, os.Stderr is set.
+	ErrOutput io.Writer
+
+	// Version information of the program.
+	//
+	// If set, a "version" command and a -version flag are added to the root command.
+	// Each is skipped if the root command or GlobalFlags already define a command or flag with the same name.
+	Version *Version
+
+	// Middleware to wrap the execution of commands, including their pre-run and post-run functions.
+	// The first middleware is the outermost one.
+	Middleware []Middleware
+
+	// HandleSignals derives the context passed to commands from the SIGINT and SIGTERM signals.
+	//
+	// The context is canceled on the first signal, and the process exits immediately on the second.
+	// When a signal is received, Run returns an ExitError with the conventional 128+signal exit code.
+	HandleSignals bool
+
+	// TimeoutFlag adds a -timeout flag to all commands, which users can use to limit how long a command runs.
+	// Its default value is the timeout of the command, if it implements the Timeouter interface.
+	//
+	// Like the other built-in flags, it is skipped for commands already defining a flag with the same name,
+	// either on their own, as a persistent flag of one of their parents, or with GlobalFlags.
+	TimeoutFlag bool
+
+	// RecoverPanics converts panics from commands into an ExitError with the ExitSoftware exit code.
+	//
+	// A crash report containing the stack trace, command, arguments (with secrets redacted), and version
+	// is saved to a temporary file, and a message pointing to it is printed to the error output.
+	// The returned error is left for PrintError to print.
+	RecoverPanics bool
+
+	// CanceledExitCode is the exit code for commands failing with a context.Canceled error.
+	// If zero, ExitCanceled is used.
+	CanceledExitCode int
+
+	// DeadlineExceededExitCode is the exit code for commands failing with a context.DeadlineExceeded error,
+	// including commands exceeding their timeout.
+	// If zero, ExitTimeout is used.
+	DeadlineExceededExitCode int
+
+	// OutputFlag adds an -output flag to all commands to choose the format PrintError uses: text or json.
+	// The format can also be set with an environment variable named after the root command, such as APP_OUTPUT.
+	// The JSON format is useful for automation, as it contains the exit code, message, command, and hint of the error.
+	//
+	// The flag is skipped for commands already defining an -output flag, or when GlobalFlags defines one,
+	// and the format is then only read from the environment variable.
+	OutputFlag bool
+
+	// FormatFlag adds a -format flag to all commands to choose the format the Print function uses:
+	// table, json, jsonl, csv, or go-template=TEMPLATE.
+	//
+	// The flag is skipped for commands already defining a -format flag, or when GlobalFlags defines one.
+	FormatFlag bool
+
+	// PromptFlags adds the -yes and -no-input flags to all commands.
+	// The -yes flag makes Confirm return true without prompting, and -no-input makes all prompts fail.
+	//
+	// Each flag is skipped for commands already defining a flag with the same name, or when GlobalFlags defines one.
+	PromptFlags bool
+
+	// Plugins resolves unknown subcommands of the root command to executables on PATH, like git does.
+	// For example, "app deploy -v" runs "app-deploy -v" if the root command has no "deploy" command.
+	//
+	// Plugins get the remaining arguments and the input and output streams of the program.
+	// Flags, hooks, and middleware of the program aren't used.
+	// If a plugin fails, ExitCode returns its exit code.
+	// The help of the root command lists the plugins found on PATH.
+	Plugins bool
+
+	// AllowFlagShadowing lets commands define flags with the same name as persistent flags of their ancestors,
+	// hiding them. The flag of the command closest to the invoked one wins.
+	//
+	// Otherwise, Run fails with an ExitSoftware error naming both commands.
+	// Flags can never shadow global or built-in flags.
+	AllowFlagShadowing bool
+
+	// UserAliases lets users define shortcuts for commands, like git aliases.
+	//
+	// Aliases are read from AliasFile and from an environment variable named after the root command, such as APP_ALIASES,
+	// with one "name = expansion" definition per line. On the environment variable, definitions can also be separated by semicolons, so they can't contain any.
+	// The expansion is split into arguments like a shell does, so arguments can be quoted:
+	// 	co = checkout -force
+	// 	msg = commit -m "quick fix"
+	// When the first argument is an alias, it is replaced by its expansion before the command is resolved.
+	// Aliases can expand to other aliases, but never shadow commands of the root command.
+	UserAliases bool
+
+	// AliasFile is the path of the file containing the aliases defined by users when UserAliases is set.
+	// It is ignored if empty or if the file doesn't exist.
+	AliasFile string
+
+	// LazyCommands avoids creating commands that aren't used, to speed up the start of programs with large command trees.
+	//
+	// Parent commands implementing the Lookuper interface are asked for the invoked subcommand only,
+	// and the command tree isn't checked for duplicated commands, so you should check it with Validate on a test instead.
+	// The Commands function of a parent is still called to print its help.
+	LazyCommands bool
+
+	// PrefixMatching lets users abbreviate commands with any unambiguous prefix of their names,
+	// such as "app dep" for "app deploy".
+	//
+	// Exact names, including user aliases and plugins, take precedence over prefixes.
+	// A prefix matching multiple commands fails with an ExitUsage error listing them.
+	PrefixMatching bool
+
+	// ArgsFiles expands arguments of the form @path before "--" with the arguments read from the file, like response files of compilers.
+	// It is useful for commands taking many arguments, such as from generated configuration.
+	//
+	// Arguments are split like a shell does, so they can be on multiple lines and quoted, and # starts a comment.
+	// Arguments read from files aren't expanded again.
+	// Use @@ to pass a literal argument starting with @, such as @@user for @user.
+	// The expansion happens before user aliases are expanded.
+	ArgsFiles bool
+
+	// Now returns the current time for the Now function.
+	//
+	// If not set, time.Now is used.
+	// You probably only want to set this for testing.
+	Now func() time.Time
+
+	fs     *flag.FlagSet
+	trail  []Command
+	output string
+
+	// commands and lookups cache the subcommands of the commands during a run, indexed by their paths.
+	commands map[string][]Command
+	lookups  map[string]Command
+
+	providers map[reflect.Type]reflect.Value
 }
 
+// contextKey for the values clino adds to the context passed to commands.
+type contextKey int
+
+const (
+	trailKey contextKey = iota
+	printerKey
+	ioKey
+	promptKey
+	clockKey
+	servicesKey
+	resolvingKey
+)
+
 // Run program by processing arguments and executing the invoked command.
 //
 // Context is passed down to the command to simplify testing and cancelation.
@@ -113,23 +321,97 @@ type Program struct {
 // 	Root: &RootCommand{},
 // }
 // if err := p.Run(context.Background(), os.Args[1:]...); err != nil {
-// 	fmt.Fprintf(os.Stderr, "%+v\n", err)
+// 	p.PrintError(err)
 // 	os.Exit(clino.ExitCode(err))
 // }
 func (p *Program) Run(ctx context.Context, args ...string) error {
+	if p.Input == nil {
+		p.Input = os.Stdin
+	}
 	if p.Output == nil {
 		p.Output = os.Stdout
 	}
+	if p.ErrOutput == nil {
+		p.ErrOutput = os.Stderr
+	}
 	if p.Root == nil {
 		panic("root command not implemented")
 	}
-	checkDuplicated(p.Root, []string{p.Root.Name()})
+	p.commands, p.lookups = map[string][]Command{}, map[string]Command{}
+	p.trail = nil // errors before the command is resolved shouldn't refer to a previous run.
+	if !p.LazyCommands {
+		checkDuplicated(p.Root, []string{p.Root.Name()})
+	}
+	if p.OutputFlag {
+		// arguments and aliases might fail to expand, so the output format must be known before.
+		p.output = p.scanOutputFormat(args)
+	}
+	var err error
+	if p.ArgsFiles {
+		if args, err = expandArgsFiles(args); err != nil {
+			return err
+		}
+	}
+	if p.UserAliases {
+		if args, err = p.expandAliases(args); err != nil {
+			return err
+		}
+	}
 	p.fs = flag.NewFlagSet("", flag.ContinueOnError)
 	p.fs.SetOutput(ioutil.Discard) // skip printing flags -help when parsing flags fail.
 	if p.GlobalFlags != nil {
 		p.GlobalFlags(p.fs)
 	}
-	return p.runCommand(ctx, args)
+	if p.OutputFlag {
+		p.output = p.scanOutputFormat(args)
+	}
+	if p.HandleSignals {
+		ctx, stop := notifySignals(ctx)
+		return p.contextExitCode(stop(p.runCommand(ctx, args)))
+	}
+	return p.contextExitCode(p.runCommand(ctx, args))
+}
+
+// defineFlags adds the persistent flags of the commands on the trail and the flags of the invoked command to the flag set.
+func (p *Program) defineFlags(trail []Command) error {
+	cf := newCommandFlags(p.fs, p.AllowFlagShadowing)
+	for i, c := range trail {
+		if f, ok := c.(PersistentFlagSet); ok && f != nil {
+			if err := cf.define("persistent flag", trail[:i+1], f.PersistentFlags); err != nil {
+				return err
+			}
+		}
+	}
+	if f, ok := trail[len(trail)-1].(FlagSet); ok && f != nil {
+		if err := cf.define("flag", trail, f.Flags); err != nil {
+			return err
+		}
+	}
+	cf.apply(p.fs)
+	return nil
+}
+
+// timeoutExitCode returns the exit code for commands exceeding their timeout.
+func (p *Program) timeoutExitCode() int {
+	if p.DeadlineExceededExitCode != 0 {
+		return p.DeadlineExceededExitCode
+	}
+	return ExitTimeout
+}
+
+// contextExitCode wraps context errors with the exit codes configured for them.
+func (p *Program) contextExitCode(err error) error {
+	switch contextExitCause(err) {
+	case context.Canceled:
+		if p.CanceledExitCode != 0 {
+			return ExitError{Code: p.CanceledExitCode, Err: err}
+		}
+	case context.DeadlineExceeded:
+		if p.DeadlineExceededExitCode != 0 {
+			return ExitError{Code: p.DeadlineExceededExitCode, Err: err}
+		}
+	}
+	return err
 }
 
 // checkDuplicated is supposed to be called initially with the root command and check the children implementations, recursively.
@@ -156,13 +438,11 @@ func isRunnable(cmd Command) bool {
 
 func commandNotFound(binary string, trail []string) error {
 	trail = append([]string{binary}, trail...)
-	return fmt.Errorf("unknown command: '%v'", strings.Join(trail, " "))
+	return UsageError(fmt.Errorf("unknown command: '%v'", strings.Join(trail, " ")))
 }
 
-func (p *Program) loadCommand(ctx context.Context, args []string) []Command {
-	commands := getSubcommands(p.Root)
-	cmdArgs := getCommandArgs(args)
-	return p.walkCommand(commands, cmdArgs)
+func (p *Program) loadCommand(ctx context.Context, args []string) ([]Command, error) {
+	return p.walkCommand(getCommandArgs(args))
 }
 
 func skipHelpCommand(args []string) []string {
@@ -173,38 +453,204 @@ func skipHelpCommand(args []string) []string {
 }
 
 func (p *Program) runCommand(ctx context.Context, args []string) error {
-	trail := p.loadCommand(ctx, skipHelpCommand(args))
+	if p.Plugins {
+		if path, ok := p.lookPlugin(args); ok {
+			return p.runPlugin(ctx, path, args[1:])
+		}
+	}
+	trail, err := p.loadCommand(ctx, skipHelpCommand(args))
+	if err != nil {
+		return err
+	}
+	flagArgs, root := args[len(trail)-1:], len(trail) == 1
+	if len(args) == 0 || args[0] != "help" {
+		invoked := len(trail)
+		trail = p.defaultCommand(trail)
+		if len(trail) != invoked && !acceptsArgs(trail[len(trail)-1], flagArgs) {
+			p.trail = trail[:invoked]
+			return commandNotFound(p.Root.Name(), append(commandNames(trail[1:invoked]), flagArgs[0]))
+		}
+	}
 	cmd := trail[len(trail)-1]
+	p.trail = trail
 
-	for _, c := range trail {
-		if f, ok := c.(PersistentFlagSet); ok && f != nil {
-			f.PersistentFlags(p.fs)
+	var version *bool
+	if p.Version != nil && root && p.builtinFlag(trail, "version") {
+		version = p.fs.Bool("version", false, "print version information")
+	}
+	if p.OutputFlag {
+		p.setOutputFormat(trail)
+	}
+	timeout := commandTimeout(cmd)
+	if p.TimeoutFlag && p.builtinFlag(trail, "timeout") {
+		p.fs.DurationVar(&timeout, "timeout", timeout, "maximum time for the command to run")
+	}
+	format := FormatTable
+	if p.FormatFlag && p.builtinFlag(trail, "format") {
+		p.fs.Var((*formatValue)(&format), "format", "output format: table, json, jsonl, csv, or go-template=TEMPLATE")
+	}
+	var prompt *promptSettings
+	if p.PromptFlags {
+		prompt = &promptSettings{}
+		if p.builtinFlag(trail, "yes") {
+			p.fs.BoolVar(&prompt.yes, "yes", false, "assume yes on confirmation prompts")
+		}
+		if p.builtinFlag(trail, "no-input") {
+			p.fs.BoolVar(&prompt.noInput, "no-input", false, "disable interactive prompts")
 		}
 	}
-	if f, ok := cmd.(FlagSet); ok && f != nil {
-		f.Flags(p.fs)
+	if err := p.defineFlags(trail); err != nil {
+		return err
 	}
-	if (len(args) == 0 && !isRunnable(p.Root)) || (len(args) != 0 && args[0] == "help") {
+	setFileValuesInput(p.fs, p.Input)
+	if (len(args) == 0 && !isRunnable(cmd)) || (len(args) != 0 && args[0] == "help") {
 		return p.runHelp(ctx, args)
 	}
 	if r, ok := cmd.(Runnable); ok && r != nil {
-		err := p.fs.Parse(args[len(trail)-1:])
+		err := p.fs.Parse(flagArgs)
 		if err == flag.ErrHelp {
 			return p.runHelp(ctx, args)
 		}
 		if err != nil {
-			return err
+			return UsageError(err)
+		}
+		if version != nil && *version {
+			return p.Version.print(p.Output, false)
+		}
+		run := chain(func(ctx context.Context, args ...string) error {
+			return runHooks(ctx, trail, r, args)
+		}, p.Middleware)
+		if p.RecoverPanics {
+			run = p.recoverPanics(run, trail, args)
 		}
-		return r.Run(ctx, p.fs.Args()...)
+		ctx = context.WithValue(ctx, trailKey, trail)
+		ctx = context.WithValue(ctx, printerKey, &Printer{Format: format, Output: p.Output})
+		ctx = context.WithValue(ctx, ioKey, IOStreams{In: p.Input, Out: p.Output, Err: p.ErrOutput})
+		if prompt != nil {
+			ctx = context.WithValue(ctx, promptKey, prompt)
+		}
+		if p.Now != nil {
+			ctx = context.WithValue(ctx, clockKey, p.Now)
+		}
+		services := newServices(p.providers)
+		ctx = context.WithValue(ctx, servicesKey, services)
+		if timeout > 0 {
+			return services.close(runWithTimeout(ctx, timeout, p.timeoutExitCode(), run, p.fs.Args()))
+		}
+		return services.close(run(ctx, p.fs.Args()...))
+	}
+	// The root command might not be runnable, but -version should still work.
+	if version != nil && p.fs.Parse(args) == nil && *version {
+		return p.Version.print(p.Output, false)
 	}
 	return p.runHelp(ctx, args)
 }
 
+// acceptsArgs reports whether a default command accepts the arguments.
+// A positional argument is only accepted if the command implements the ArgsAccepter interface.
+func acceptsArgs(cmd Command, args []string) bool {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return true
+	}
+	a, ok := cmd.(ArgsAccepter)
+	return ok && a != nil && a.AcceptsArgs()
+}
+
+// commandNames returns the names of the commands.
+func commandNames(commands []Command) []string {
+	var names []string
+	for _, c := range commands {
+		names = append(names, c.Name())
+	}
+	return names
+}
+
+// builtinFlag reports whether a built-in flag should be added to the flag set.
+// Built-in flags give way to global flags and flags of the commands on the trail with the same name.
+func (p *Program) builtinFlag(trail []Command, name string) bool {
+	return p.fs.Lookup(name) == nil && !definesFlag(trail, name)
+}
+
+// definesFlag reports whether the persistent flags of the commands on the trail,
+// or the flags of the invoked command, include a flag with the given name.
+func definesFlag(trail []Command, name string) bool {
+	var defines []func(*flag.FlagSet)
+	for _, c := range trail {
+		if f, ok := c.(PersistentFlagSet); ok && f != nil {
+			defines = append(defines, f.PersistentFlags)
+		}
+	}
+	if f, ok := trail[len(trail)-1].(FlagSet); ok && f != nil {
+		defines = append(defines, f.Flags)
+	}
+	for _, define := range defines {
+		names, _ := flagNames(define)
+		for _, n := range names {
+			if n == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// defaultCommand appends the default subcommand of the last command on the trail to it,
+// recursively, if the command isn't runnable and implements the DefaultCommander interface.
+func (p *Program) defaultCommand(trail []Command) []Command {
+	for {
+		cmd := trail[len(trail)-1]
+		d, ok := cmd.(DefaultCommander)
+		if !ok || d == nil || isRunnable(cmd) {
+			return trail
+		}
+		c, ok := p.lookupCommand(trail, d.DefaultCommand())
+		if !ok {
+			return trail
+		}
+		trail = append(trail, c)
+	}
+}
+
+// runHooks calls the Run function of the command surrounded by the pre-run and post-run functions of the trail.
+// Post-run functions are deferred as soon as their matching pre-run function succeeds,
+// so they are called in reverse order, even if a later pre-run function or Run fails.
+// The first error that happens is returned.
+func runHooks(ctx context.Context, trail []Command, r Runnable, args []string) (err error) {
+	after := func(post func(ctx context.Context, args ...string) error) {
+		if perr := post(ctx, args...); err == nil {
+			err = perr
+		}
+	}
+	for _, c := range trail {
+		if h, ok := c.(PersistentPreRunner); ok && h != nil {
+			if err = h.PersistentPreRun(ctx, args...); err != nil {
+				return err
+			}
+		}
+		if h, ok := c.(PersistentPostRunner); ok && h != nil {
+			defer after(h.PersistentPostRun)
+		}
+	}
+	cmd := trail[len(trail)-1]
+	if h, ok := cmd.(PreRunner); ok && h != nil {
+		if err = h.PreRun(ctx, args...); err != nil {
+			return err
+		}
+	}
+	if h, ok := cmd.(PostRunner); ok && h != nil {
+		defer after(h.PostRun)
+	}
+	return r.Run(ctx, args...)
+}
+
 func (p *Program) runHelp(ctx context.Context, args []string) error {
 	if len(args) >= 1 && args[0] == "help" {
 		args = args[1:]
 	}
-	trail := p.walkCommand(getSubcommands(p.Root), getCommandArgs(args))
+	trail, err := p.walkCommand(getCommandArgs(args))
+	if err != nil {
+		return err
+	}
 	cmd := trail[len(trail)-1]
 
 	var breadcrumb []string
@@ -213,14 +659,18 @@ func (p *Program) runHelp(ctx context.Context, args []string) error {
 	}
 	breadcrumb = breadcrumb[1:]
 
+	commands := p.subcommands(trail)
 	h := &helper{
 		Output:   p.Output,
-		Commands: getSubcommands(cmd),
+		Commands: commands,
 		binary:   p.Root.Name(),
 		trail:    breadcrumb,
 		args:     args,
 		fs:       p.fs,
 	}
+	if p.Plugins && len(trail) == 1 {
+		h.Plugins = p.plugins()
+	}
 	if l, ok := cmd.(Longer); ok && l != nil {
 		h.Long = l.Long
 	}
@@ -253,18 +703,84 @@ func getCommand(commands []Command, name string) (cmd Command, ok bool) {
 // walkCommand is similar to getCommand, but recursive and it stops
 // when it can't find any further command following the path.
 // The returned trail value is the "breadcrumb" for the command.
-func (p *Program) walkCommand(commands []Command, names []string) (trail []Command) {
+// It fails if PrefixMatching is set and a name is an ambiguous prefix.
+func (p *Program) walkCommand(names []string) (trail []Command, err error) {
 	trail = append(trail, p.Root)
-	current := commands
 	for _, name := range names {
-		c, next := getCommand(current, name)
+		c, next := p.lookupCommand(trail, name)
+		if !next && p.PrefixMatching {
+			if c, next, err = p.matchPrefix(trail, name); err != nil {
+				return nil, err
+			}
+		}
 		if !next {
-			return
+			return trail, nil
 		}
 		trail = append(trail, c)
-		current = getSubcommands(c)
 	}
-	return
+	return trail, nil
+}
+
+// matchPrefix returns the only subcommand of the last command on the trail with a name starting with prefix.
+func (p *Program) matchPrefix(trail []Command, prefix string) (Command, bool, error) {
+	var matches []Command
+	if prefix == "" {
+		return nil, false, nil
+	}
+	for _, c := range p.subcommands(trail) {
+		if strings.HasPrefix(c.Name(), prefix) {
+			matches = append(matches, c)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, false, nil
+	case 1:
+		return matches[0], true, nil
+	}
+	var names []string
+	for _, c := range matches {
+		names = append(names, c.Name())
+	}
+	return nil, false, UsageError(fmt.Errorf("ambiguous command: '%s %s' could be %s",
+		commandPath(trail), prefix, strings.Join(names, ", ")))
+}
+
+// lookupCommand returns the subcommand of the last command on the trail with the given name.
+// With LazyCommands, it uses the Lookuper interface if the command implements it.
+func (p *Program) lookupCommand(trail []Command, name string) (Command, bool) {
+	l, ok := trail[len(trail)-1].(Lookuper)
+	if !p.LazyCommands || !ok || l == nil {
+		return getCommand(p.subcommands(trail), name)
+	}
+	key := commandPath(trail) + " " + name
+	if c, ok := p.lookups[key]; ok {
+		return c, true
+	}
+	c := l.Lookup(name)
+	if c == nil && len(trail) == 1 && p.Version != nil && name == "version" {
+		c = &versionCommand{p: p}
+	}
+	if c == nil {
+		return nil, false
+	}
+	p.lookups[key] = c
+	return c, true
+}
+
+// subcommands returns the subcommands of the last command on the trail.
+// The Commands function of each command is called at most once per run, so the same instances are used.
+func (p *Program) subcommands(trail []Command) []Command {
+	key := commandPath(trail)
+	if commands, ok := p.commands[key]; ok {
+		return commands
+	}
+	commands := getSubcommands(trail[len(trail)-1])
+	if len(trail) == 1 {
+		commands = p.withBuiltins(commands)
+	}
+	p.commands[key] = commands
+	return commands
 }
 
 func getCommandArgs(args []string) (out []string) {
@@ -280,6 +796,22 @@ func getCommandArgs(args []string) (out []string) {
 	return
 }
 
+// rootCommands returns the subcommands of the root command, including any built-in commands.
+func (p *Program) rootCommands() []Command {
+	return p.subcommands([]Command{p.Root})
+}
+
+// withBuiltins adds the built-in commands to the subcommands of the root command.
+// A built-in command is skipped if the root command already has a command with the same name.
+func (p *Program) withBuiltins(commands []Command) []Command {
+	if p.Version != nil {
+		if _, ok := getCommand(commands, "version"); !ok {
+			commands = append(commands, &versionCommand{p: p})
+		}
+	}
+	return commands
+}
+
 func getSubcommands(cmd Command) []Command {
 	if p, ok := cmd.(Parent); ok && p != nil {
 		return p.Commands()
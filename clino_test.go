@@ -5,9 +5,13 @@ import (
 	"context"
 	"errors"
 	"flag"
+	"fmt"
 	"io/ioutil"
+	"os"
 	"os/exec"
 	"reflect"
+	"runtime"
+	"syscall"
 	"testing"
 )
 
@@ -446,6 +450,41 @@ func TestExitCode(t *testing.T) {
 			in:   errors.New("cannot find error code"),
 			want: 1,
 		},
+		{
+			desc: "exit coder",
+			in:   fmt.Errorf("cannot deploy: %w", quotaError{}),
+			want: ExitTempFail,
+		},
+		{
+			desc: "exit error wrapping exit coder",
+			in:   NoPermError(quotaError{}),
+			want: ExitNoPerm,
+		},
+		{
+			desc: "usage error",
+			in:   UsageError(errors.New("missing argument")),
+			want: ExitUsage,
+		},
+		{
+			desc: "config error",
+			in:   ConfigError(errors.New("missing configuration file")),
+			want: ExitConfig,
+		},
+		{
+			desc: "context canceled",
+			in:   fmt.Errorf("cannot download: %w", context.Canceled),
+			want: ExitCanceled,
+		},
+		{
+			desc: "context deadline exceeded",
+			in:   context.DeadlineExceeded,
+			want: ExitTimeout,
+		},
+		{
+			desc: "exit error wrapping context canceled",
+			in:   UnavailableError(context.Canceled),
+			want: ExitUnavailable,
+		},
 		{
 			desc: "copy regular program error code",
 			// explanation: by default, Go binary exits with error code = 2.
@@ -465,6 +504,109 @@ func TestExitCode(t *testing.T) {
 	}
 }
 
+func TestExitCodeSignaledChildProcess(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sending signals isn't supported on Windows")
+	}
+	err := exec.Command("sh", "-c", "kill -TERM $$").Run()
+	if want := 128 + int(syscall.SIGTERM); ExitCode(err) != want {
+		t.Errorf("wanted ExitCode(%v) = %v, got %v instead", err, want, ExitCode(err))
+	}
+}
+
+func TestProgramContextExitCodes(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	testCases := []struct {
+		desc    string
+		ctx     context.Context
+		program Program
+		want    int
+	}{
+		{
+			desc:    "canceled",
+			ctx:     canceled,
+			program: Program{Root: &timeoutCommand{}},
+			want:    ExitCanceled,
+		},
+		{
+			desc:    "canceled with custom exit code",
+			ctx:     canceled,
+			program: Program{Root: &timeoutCommand{}, CanceledExitCode: 1},
+			want:    1,
+		},
+		{
+			desc:    "deadline exceeded",
+			ctx:     expired,
+			program: Program{Root: &timeoutCommand{}},
+			want:    ExitTimeout,
+		},
+		{
+			desc:    "deadline exceeded with custom exit code",
+			ctx:     expired,
+			program: Program{Root: &timeoutCommand{}, DeadlineExceededExitCode: ExitTempFail},
+			want:    ExitTempFail,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := tc.program.Run(tc.ctx)
+			if code := ExitCode(err); code != tc.want {
+				t.Errorf("wanted exit code %d, got %d instead", tc.want, code)
+			}
+			if !errors.Is(err, tc.ctx.Err()) {
+				t.Errorf("wanted error to wrap %v, got %v instead", tc.ctx.Err(), err)
+			}
+		})
+	}
+}
+
+func TestExitErrorConstructorsNil(t *testing.T) {
+	for _, f := range []func(error) error{
+		UsageError,
+		DataError,
+		NoInputError,
+		UnavailableError,
+		SoftwareError,
+		TempFailError,
+		NoPermError,
+		ConfigError,
+	} {
+		if err := f(nil); err != nil {
+			t.Errorf("wanted nil error, got %v instead", err)
+		}
+	}
+}
+
+func TestProgramUsageErrorExitCode(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		program Program
+		args    []string
+	}{
+		{
+			desc:    "undefined flag",
+			program: Program{Root: &simpleCommand{}},
+			args:    []string{"-undefined"},
+		},
+		{
+			desc:    "command not found",
+			program: Program{Root: &rootCommand{}},
+			args:    []string{"notfound"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			tc.program.Output = ioutil.Discard
+			if code := ExitCode(tc.program.Run(context.Background(), tc.args...)); code != ExitUsage {
+				t.Errorf("wanted exit code %d, got %d instead", ExitUsage, code)
+			}
+		})
+	}
+}
+
 func TestExitError(t *testing.T) {
 	err := errors.New("this is the original error")
 	ee := ExitError{
@@ -478,3 +620,307 @@ func TestExitError(t *testing.T) {
 		t.Error("expected wrapped error to print the same error message")
 	}
 }
+
+func TestProgramHooks(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		args  []string
+		fail  map[string]error
+		err   string
+		calls []string
+	}{
+		{
+			desc: "run",
+			args: []string{"parent", "child", "-verbose", "x"},
+			calls: []string{
+				"root persistent pre-run (verbose=true)",
+				"parent persistent pre-run",
+				"child pre-run [x]",
+				"child run [x]",
+				"child post-run [x]",
+				"root persistent post-run",
+			},
+		},
+		{
+			desc: "run fails",
+			args: []string{"parent", "child"},
+			fail: map[string]error{
+				"child run []":             errors.New("run failure"),
+				"root persistent post-run": errors.New("root post-run failure"),
+			},
+			err: "run failure",
+			calls: []string{
+				"root persistent pre-run (verbose=false)",
+				"parent persistent pre-run",
+				"child pre-run []",
+				"child run []",
+				"child post-run []",
+				"root persistent post-run",
+			},
+		},
+		{
+			desc: "pre-run fails",
+			args: []string{"parent", "child"},
+			fail: map[string]error{
+				"child pre-run []": errors.New("pre-run failure"),
+			},
+			err: "pre-run failure",
+			calls: []string{
+				"root persistent pre-run (verbose=false)",
+				"parent persistent pre-run",
+				"child pre-run []",
+				"root persistent post-run",
+			},
+		},
+		{
+			desc: "persistent pre-run fails",
+			args: []string{"parent", "child"},
+			fail: map[string]error{
+				"root persistent pre-run (verbose=false)": errors.New("persistent pre-run failure"),
+			},
+			err: "persistent pre-run failure",
+			calls: []string{
+				"root persistent pre-run (verbose=false)",
+			},
+		},
+		{
+			desc: "post-run fails",
+			args: []string{"parent", "child"},
+			fail: map[string]error{
+				"child post-run []": errors.New("post-run failure"),
+			},
+			err: "post-run failure",
+			calls: []string{
+				"root persistent pre-run (verbose=false)",
+				"parent persistent pre-run",
+				"child pre-run []",
+				"child run []",
+				"child post-run []",
+				"root persistent post-run",
+			},
+		},
+		{
+			desc: "help doesn't run hooks",
+			args: []string{"parent", "child", "-h"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			log := &hooksLog{fail: tc.fail}
+			p := Program{
+				Root:   &hooksRootCommand{log: log},
+				Output: ioutil.Discard,
+			}
+			err := p.Run(context.Background(), tc.args...)
+			if (err == nil && tc.err != "") || (err != nil && err.Error() != tc.err) {
+				t.Errorf("wanted error to be %v, got %v instead", tc.err, err)
+			}
+			if !reflect.DeepEqual(log.calls, tc.calls) {
+				t.Errorf("wanted calls %q, got %q instead", tc.calls, log.calls)
+			}
+		})
+	}
+}
+
+func TestProgramDefaultCommand(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		def      string
+		args     []string
+		wantCmd  string
+		wantArgs []string
+		wantAll  bool
+		wantErr  string
+		accept   bool
+	}{
+		{
+			desc:     "default command",
+			def:      "show",
+			args:     []string{"config"},
+			wantCmd:  "show",
+			wantArgs: []string{},
+		},
+		{
+			desc:     "arguments are forwarded",
+			def:      "show",
+			args:     []string{"config", "-all", "name"},
+			wantCmd:  "show",
+			wantArgs: []string{"name"},
+			wantAll:  true,
+		},
+		{
+			desc:     "default command of the root command",
+			def:      "show",
+			args:     []string{},
+			wantCmd:  "show",
+			wantArgs: []string{},
+		},
+		{
+			desc:     "subcommand",
+			def:      "show",
+			args:     []string{"config", "list", "name"},
+			wantCmd:  "list",
+			wantArgs: []string{"name"},
+		},
+		{
+			desc:    "mistyped subcommand",
+			def:     "show",
+			args:    []string{"config", "delte"},
+			wantErr: "unknown command: 'app config delte'",
+		},
+		{
+			desc:    "mistyped subcommand of the root command",
+			def:     "show",
+			args:    []string{"confg"},
+			wantErr: "unknown command: 'app confg'",
+		},
+		{
+			desc:     "positional arguments",
+			def:      "show",
+			args:     []string{"config", "name", "-all"},
+			wantCmd:  "show",
+			wantArgs: []string{"name", "-all"},
+			accept:   true,
+		},
+		{
+			desc: "help",
+			def:  "show",
+			args: []string{"help", "config"},
+		},
+		{
+			desc: "missing default command",
+			def:  "missing",
+			args: []string{"config"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			show := &treeCommand{name: "show", flags: []string{"all"}}
+			list := &treeCommand{name: "list"}
+			config := &defaultParentCommand{name: "config", def: tc.def, children: []Command{show, list}}
+			if tc.accept {
+				config.children[0] = argsCommand{show}
+			}
+			p := Program{
+				Root:   &defaultParentCommand{name: "app", def: "config", children: []Command{config}},
+				Output: ioutil.Discard,
+			}
+			err := p.Run(context.Background(), tc.args...)
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Errorf("wanted error %q, got %v instead", tc.wantErr, err)
+				}
+				if code := ExitCode(err); code != ExitUsage {
+					t.Errorf("wanted exit code %d, got %d instead", ExitUsage, code)
+				}
+			} else if err != nil {
+				t.Errorf("wanted error to be nil, got %v instead", err)
+			}
+			for _, c := range []*treeCommand{show, list} {
+				if c.Name() != tc.wantCmd {
+					if c.args != nil {
+						t.Errorf("wanted command %q not to run", c.Name())
+					}
+					continue
+				}
+				if !reflect.DeepEqual(c.args, tc.wantArgs) {
+					t.Errorf("got arguments %q, wanted %q", c.args, tc.wantArgs)
+				}
+			}
+			if tc.wantCmd == "show" && *show.values["all"] != tc.wantAll {
+				t.Errorf("wanted -all flag to be %v", tc.wantAll)
+			}
+		})
+	}
+}
+
+func TestProgramPrefixMatching(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		disabled bool
+		aliases  string
+		args     []string
+		wantCmd  string
+		wantErr  string
+	}{
+		{
+			desc:    "prefix",
+			args:    []string{"dep", "now"},
+			wantCmd: "deploy",
+		},
+		{
+			desc:    "exact name",
+			args:    []string{"de"},
+			wantCmd: "de",
+		},
+		{
+			desc:    "ambiguous",
+			args:    []string{"del"},
+			wantErr: "ambiguous command: 'app del' could be delete, delay",
+		},
+		{
+			desc:    "nested",
+			args:    []string{"se", "st"},
+			wantCmd: "start",
+		},
+		{
+			desc:    "aliases take precedence",
+			aliases: "del = deploy",
+			args:    []string{"del"},
+			wantCmd: "deploy",
+		},
+		{
+			desc:    "aliases can use prefixes",
+			aliases: "d = dep -force",
+			args:    []string{"d"},
+			wantCmd: "deploy",
+		},
+		{
+			desc:     "disabled",
+			disabled: true,
+			args:     []string{"dep"},
+			wantErr:  "unknown command: 'app dep'",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			os.Setenv("APP_ALIASES", tc.aliases)
+			defer os.Unsetenv("APP_ALIASES")
+			commands := []*treeCommand{
+				{name: "deploy", flags: []string{"force"}},
+				{name: "delete"},
+				{name: "delay"},
+				{name: "de"},
+				{name: "start"},
+			}
+			server := &defaultParentCommand{name: "server", children: []Command{commands[4]}}
+			p := Program{
+				Root: &defaultParentCommand{
+					name:     "app",
+					children: []Command{commands[0], commands[1], commands[2], commands[3], server},
+				},
+				Output:         ioutil.Discard,
+				UserAliases:    true,
+				PrefixMatching: !tc.disabled,
+			}
+			err := p.Run(context.Background(), tc.args...)
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Errorf("wanted error %q, got %v instead", tc.wantErr, err)
+				}
+				if code := ExitCode(err); code != ExitUsage {
+					t.Errorf("wanted exit code %d, got %d instead", ExitUsage, code)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("wanted error to be nil, got %v instead", err)
+			}
+			for _, c := range commands {
+				if ran := c.args != nil; ran != (c.name == tc.wantCmd) {
+					t.Errorf("wanted command %q to run: %v, got %v instead", c.name, c.name == tc.wantCmd, ran)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+// Package clinotest provides utilities for testing programs created with clino.
+//
+// Use Runner to run a clino.Program with captured input and output streams,
+// environment variables, a fake clock, and a fake terminal,
+// and Golden to compare the output with golden files.
+// 	func TestHelp(t *testing.T) {
+// 		r := clinotest.Runner{}
+// 		res := r.Run(t, &clino.Program{Root: &RootCommand{}}, "help")
+// 		if res.Err != nil {
+// 			t.Errorf("wanted error to be nil, got %v instead", res.Err)
+// 		}
+// 		clinotest.Golden(t, "testdata/help.golden", res.Stdout)
+// 	}
+// Run your tests with the -clinotest.update flag to update the golden files.
+// If your test package defines its own -update flag, it works too.
+package clinotest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/henvic/clino"
+)
+
+// update is namespaced so it doesn't conflict with -update flags defined by test packages.
+var update = flag.Bool("clinotest.update", false, "update golden files")
+
+// updating reports whether golden files should be updated,
+// either with the -clinotest.update flag or a boolean -update flag defined by the test package.
+func updating() bool {
+	if *update {
+		return true
+	}
+	if f := flag.Lookup("update"); f != nil {
+		if g, ok := f.Value.(flag.Getter); ok {
+			b, _ := g.Get().(bool)
+			return b
+		}
+	}
+	return false
+}
+
+// Runner runs programs in a controlled environment for testing.
+type Runner struct {
+	// Stdin is the input of the program.
+	Stdin string
+
+	// Env contains environment variables to set while the program runs.
+	// They are restored once it finishes, so tests using them shouldn't run in parallel.
+	Env map[string]string
+
+	// Now is the time returned by clino.Now during the run. If zero, the real clock is used.
+	Now time.Time
+
+	// Terminal simulates input and output streams connected to a terminal.
+	Terminal bool
+
+	// Width and Height of the simulated terminal.
+	// If zero, the terminal has 80 columns and 24 lines.
+	Width, Height int
+}
+
+// Result of running a program.
+type Result struct {
+	// Stdout contains what the program wrote to its output.
+	Stdout string
+
+	// Stderr contains what the program wrote to its error output.
+	Stderr string
+
+	// Err returned by the program.
+	Err error
+
+	// ExitCode for the error returned by the program.
+	ExitCode int
+}
+
+// Run the program with the given arguments.
+//
+// The input, output, and error output of the program are replaced.
+// If set, the Now function of the program is replaced too.
+func (r *Runner) Run(t testing.TB, p *clino.Program, args ...string) *Result {
+	t.Helper()
+	restore := setenv(t, r.Env)
+	defer restore()
+
+	stdout, stderr := r.newStream(), r.newStream()
+	p.Input = &input{Reader: strings.NewReader(r.Stdin), terminal: r.terminal()}
+	p.Output, p.ErrOutput = stdout, stderr
+	if !r.Now.IsZero() {
+		p.Now = func() time.Time { return r.Now }
+	}
+	err := p.Run(context.Background(), args...)
+	return &Result{
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		Err:      err,
+		ExitCode: clino.ExitCode(err),
+	}
+}
+
+func (r *Runner) newStream() *output {
+	return &output{terminal: r.terminal()}
+}
+
+func (r *Runner) terminal() *terminal {
+	if !r.Terminal {
+		return nil
+	}
+	t := &terminal{
+		width:  r.Width,
+		height: r.Height,
+	}
+	if t.width == 0 {
+		t.width = 80
+	}
+	if t.height == 0 {
+		t.height = 24
+	}
+	return t
+}
+
+// terminal simulates a terminal. If nil, the stream isn't a terminal.
+type terminal struct {
+	width, height int
+}
+
+// IsTerminal reports whether the stream is a terminal.
+func (t *terminal) IsTerminal() bool {
+	return t != nil
+}
+
+// TerminalSize returns the size of the simulated terminal.
+func (t *terminal) TerminalSize() (width, height int) {
+	if t == nil {
+		return 0, 0
+	}
+	return t.width, t.height
+}
+
+type input struct {
+	*strings.Reader
+	*terminal
+}
+
+type output struct {
+	bytes.Buffer
+	*terminal
+}
+
+// setenv sets the environment variables, returning a function to restore them.
+func setenv(t testing.TB, env map[string]string) (restore func()) {
+	t.Helper()
+	type previous struct {
+		value string
+		ok    bool
+	}
+	prev := map[string]previous{}
+	for k, v := range env {
+		old, ok := os.LookupEnv(k)
+		prev[k] = previous{old, ok}
+		if err := os.Setenv(k, v); err != nil {
+			t.Fatalf("cannot set environment variable %s: %v", k, err)
+		}
+	}
+	return func() {
+		for k, p := range prev {
+			if p.ok {
+				os.Setenv(k, p.value)
+			} else {
+				os.Unsetenv(k)
+			}
+		}
+	}
+}
+
+// Golden compares got with the contents of the golden file.
+//
+// When tests run with the -clinotest.update or -update flag, the golden file is updated with got instead.
+func Golden(t testing.TB, path, got string) {
+	t.Helper()
+	if updating() {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(got), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("opening %s: %v", path, err)
+	}
+	if got != string(bs) {
+		t.Errorf("got output %v\n, wanted %v", got, string(bs))
+	}
+}
+
+// Validate the command tree with clino.Validate, reporting each problem found as a test error.
+// 	func TestCommands(t *testing.T) {
+// 		clinotest.Validate(t, &RootCommand{})
+// 	}
+func Validate(t testing.TB, root clino.Command) {
+	t.Helper()
+	err := clino.Validate(root)
+	var ve *clino.ValidationError
+	if !errors.As(err, &ve) {
+		if err != nil {
+			t.Error(err)
+		}
+		return
+	}
+	for _, problem := range ve.Problems {
+		t.Error(problem)
+	}
+}
@@ -0,0 +1,168 @@
+package clinotest
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/henvic/clino"
+)
+
+// reportCommand prints information about the environment it runs in.
+type reportCommand struct {
+	fail bool
+}
+
+func (rc *reportCommand) Name() string {
+	return "report"
+}
+
+func (rc *reportCommand) Long() string {
+	return "Report the environment."
+}
+
+func (rc *reportCommand) Flags(flags *flag.FlagSet) {
+	flags.BoolVar(&rc.fail, "fail", false, "fail with a usage error")
+}
+
+func (rc *reportCommand) Run(ctx context.Context, args ...string) error {
+	streams := clino.IO(ctx)
+	in, err := ioutil.ReadAll(streams.In)
+	if err != nil {
+		return err
+	}
+	width, height, ok := streams.TerminalSize()
+	fmt.Fprintf(streams.Out, "input: %q\n", in)
+	fmt.Fprintf(streams.Out, "terminal: %v %v %v (%dx%d, %v)\n",
+		streams.IsInputTerminal(), streams.IsOutputTerminal(), streams.IsErrorTerminal(), width, height, ok)
+	fmt.Fprintf(streams.Out, "env: %q\n", os.Getenv("CLINOTEST_NAME"))
+	fmt.Fprintf(streams.Out, "now: %v\n", clino.Now(ctx).Format(time.RFC3339))
+	fmt.Fprintf(streams.Err, "args: %q\n", args)
+	if rc.fail {
+		return clino.UsageError(errors.New("failure"))
+	}
+	return nil
+}
+
+func TestRunner(t *testing.T) {
+	if err := os.Setenv("CLINOTEST_NAME", "original"); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Unsetenv("CLINOTEST_NAME")
+	r := Runner{
+		Stdin: "Hello, World!",
+		Env: map[string]string{
+			"CLINOTEST_NAME":  "Gopher",
+			"CLINOTEST_EMPTY": "",
+		},
+		Now:      time.Date(2021, 4, 1, 10, 0, 0, 0, time.UTC),
+		Terminal: true,
+		Width:    120,
+	}
+	res := r.Run(t, &clino.Program{Root: &reportCommand{}}, "-fail", "x")
+	if res.Err == nil || res.Err.Error() != "failure" {
+		t.Errorf("wanted error to be failure, got %v instead", res.Err)
+	}
+	if res.ExitCode != clino.ExitUsage {
+		t.Errorf("wanted exit code %d, got %d instead", clino.ExitUsage, res.ExitCode)
+	}
+	Golden(t, "testdata/runner.golden", res.Stdout)
+	if want := "args: [\"x\"]\n"; res.Stderr != want {
+		t.Errorf("got error output %q, wanted %q", res.Stderr, want)
+	}
+	if got := os.Getenv("CLINOTEST_NAME"); got != "original" {
+		t.Errorf("expected environment variable to be restored, got %q instead", got)
+	}
+	if _, ok := os.LookupEnv("CLINOTEST_EMPTY"); ok {
+		t.Error("expected environment variable to be unset")
+	}
+}
+
+func TestRunnerNotTerminal(t *testing.T) {
+	r := Runner{
+		Now: time.Date(2021, 4, 1, 10, 0, 0, 0, time.UTC),
+	}
+	res := r.Run(t, &clino.Program{Root: &reportCommand{}})
+	if res.Err != nil {
+		t.Errorf("wanted error to be nil, got %v instead", res.Err)
+	}
+	if res.ExitCode != 0 {
+		t.Errorf("wanted exit code 0, got %d instead", res.ExitCode)
+	}
+	Golden(t, "testdata/runner_not_terminal.golden", res.Stdout)
+}
+
+func TestRunnerHelp(t *testing.T) {
+	var r Runner
+	res := r.Run(t, &clino.Program{Root: &reportCommand{}}, "help")
+	if res.Err != nil {
+		t.Errorf("wanted error to be nil, got %v instead", res.Err)
+	}
+	Golden(t, "testdata/help.golden", res.Stdout)
+}
+
+// recorder records test errors.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Error(args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprint(args...))
+}
+
+func TestValidate(t *testing.T) {
+	Validate(t, &reportCommand{})
+
+	var r recorder
+	Validate(&r, &parentCommand{children: []clino.Command{&reportCommand{}, &reportCommand{}}})
+	want := []string{
+		"command 'app report' is missing a short description",
+		"command implemented multiple times: 'app report'",
+	}
+	if !reflect.DeepEqual(r.errors, want) {
+		t.Errorf("got errors %q, wanted %q", r.errors, want)
+	}
+}
+
+type parentCommand struct {
+	children []clino.Command
+}
+
+func (pc *parentCommand) Name() string {
+	return "app"
+}
+
+func (pc *parentCommand) Commands() []clino.Command {
+	return pc.children
+}
+
+func TestGoldenUpdateFlag(t *testing.T) {
+	// test packages can define their own -update flag.
+	fs := flag.CommandLine
+	defer func() {
+		flag.CommandLine = fs
+	}()
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	flag.Bool("update", true, "update golden files")
+
+	dir, err := ioutil.TempDir("", "clinotest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "testdata", "updated.golden")
+	Golden(t, path, "updated\n")
+	if bs, err := ioutil.ReadFile(path); err != nil || string(bs) != "updated\n" {
+		t.Errorf("wanted golden file to be updated, got (%q, %v) instead", bs, err)
+	}
+}
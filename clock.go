@@ -0,0 +1,25 @@
+package clino
+
+import (
+	"context"
+	"time"
+)
+
+// Now returns the current time from the clock of the program running the command.
+//
+// Use it instead of time.Now in your commands so you can test them with a fake clock by setting Program.Now.
+// If the context doesn't come from a command run by Program, or Program.Now isn't set, it returns time.Now().
+func Now(ctx context.Context) time.Time {
+	if now, ok := ctx.Value(clockKey).(func() time.Time); ok {
+		return now()
+	}
+	return time.Now()
+}
+
+// now returns the current time from the clock of the program.
+func (p *Program) now() time.Time {
+	if p.Now != nil {
+		return p.Now()
+	}
+	return time.Now()
+}
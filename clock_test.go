@@ -0,0 +1,33 @@
+package clino
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestNow(t *testing.T) {
+	now := time.Date(2021, 4, 1, 10, 0, 0, 0, time.UTC)
+	var got time.Time
+	p := Program{
+		Root: &failCommand{},
+		Now:  func() time.Time { return now },
+		Middleware: []Middleware{
+			func(next RunFunc) RunFunc {
+				return func(ctx context.Context, args ...string) error {
+					got = Now(ctx)
+					return next(ctx, args...)
+				}
+			},
+		},
+	}
+	if err := p.Run(context.Background()); err != nil {
+		t.Errorf("wanted error to be nil, got %v instead", err)
+	}
+	if !got.Equal(now) {
+		t.Errorf("wanted time to be %v, got %v instead", now, got)
+	}
+	if got := Now(context.Background()); got.IsZero() {
+		t.Error("wanted current time outside program")
+	}
+}
@@ -0,0 +1,119 @@
+package clino
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// recoverPanics wraps the run function, converting panics into an ExitError with the ExitSoftware code.
+// A crash report is written to a temporary file, and a message pointing to it is printed to the error output.
+// The error isn't printed, as it is returned to be printed with PrintError.
+func (p *Program) recoverPanics(run RunFunc, trail []Command, args []string) RunFunc {
+	return func(ctx context.Context, cargs ...string) (err error) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+			binary := p.Root.Name()
+			report := p.crashReport(r, debug.Stack(), trail, args)
+			if name, werr := saveCrashReport(binary, report); werr != nil {
+				fmt.Fprintf(p.ErrOutput, "Cannot save crash report: %v\n", werr)
+			} else {
+				fmt.Fprintf(p.ErrOutput, "A crash report was saved to %s\nPlease attach it when reporting this issue.\n", name)
+			}
+			err = ExitError{
+				Code: ExitSoftware,
+				Err:  fmt.Errorf("panic: %v", r),
+			}
+		}()
+		return run(ctx, cargs...)
+	}
+}
+
+// crashReport containing the panic value, stack, command trail, arguments (with secrets redacted), and version.
+func (p *Program) crashReport(r interface{}, stack []byte, trail []Command, args []string) []byte {
+	var names []string
+	for _, c := range trail {
+		names = append(names, c.Name())
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s crash report\n\n", p.Root.Name())
+	fmt.Fprintf(&buf, "Time: %s\n", p.now().Format(time.RFC3339))
+	fmt.Fprintf(&buf, "Command: %s\n", strings.Join(names, " "))
+	fmt.Fprintf(&buf, "Arguments: %q\n", redactArgs(args, p.fs))
+	if p.Version != nil {
+		v := p.Version.resolve()
+		fmt.Fprintf(&buf, "Version: %s\n", v.Version)
+		if v.Commit != "" {
+			fmt.Fprintf(&buf, "Commit: %s\n", v.Commit)
+		}
+	}
+	fmt.Fprintf(&buf, "Go version: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&buf, "Panic: %v\n\n%s", r, stack)
+	return buf.Bytes()
+}
+
+func saveCrashReport(binary string, report []byte) (name string, err error) {
+	f, err := ioutil.TempFile("", binary+"-crash-*.txt")
+	if err != nil {
+		return "", err
+	}
+	if _, err = f.Write(report); err != nil {
+		f.Close()
+		return "", err
+	}
+	return f.Name(), f.Close()
+}
+
+// secretFlag matches flag names that are likely to hold secrets.
+var secretFlag = regexp.MustCompile(`(?i)pass|secret|token|key|credential|auth`)
+
+const redacted = "[REDACTED]"
+
+// redactArgs returns a copy of the arguments with the values of flags that look like secrets redacted.
+func redactArgs(args []string, fs *flag.FlagSet) []string {
+	out := make([]string, len(args))
+	copy(out, args)
+	for i := 0; i < len(out); i++ {
+		arg := out[i]
+		if arg == "--" {
+			break
+		}
+		name := strings.TrimLeft(arg, "-")
+		if name == arg || name == "" {
+			continue
+		}
+		if eq := strings.Index(name, "="); eq != -1 {
+			if secretFlag.MatchString(name[:eq]) {
+				out[i] = arg[:len(arg)-len(name)+eq+1] + redacted
+			}
+			continue
+		}
+		if secretFlag.MatchString(name) && !isBoolFlag(fs, name) && i+1 < len(out) {
+			i++
+			out[i] = redacted
+		}
+	}
+	return out
+}
+
+func isBoolFlag(fs *flag.FlagSet, name string) bool {
+	if fs == nil {
+		return false
+	}
+	f := fs.Lookup(name)
+	if f == nil {
+		return false
+	}
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
@@ -0,0 +1,116 @@
+package clino
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestProgramRecoverPanics(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clino")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	tmpdir := os.Getenv("TMPDIR")
+	defer os.Setenv("TMPDIR", tmpdir)
+	if err = os.Setenv("TMPDIR", dir); err != nil {
+		t.Fatal(err)
+	}
+
+	var stderr bytes.Buffer
+	p := Program{
+		Root:          &panicCommand{},
+		ErrOutput:     &stderr,
+		RecoverPanics: true,
+		Version:       &Version{Version: "1.2.3"},
+	}
+	err = p.Run(context.Background(), "-token", "s3cr3t", "arg")
+	if want := "panic: something went wrong"; err == nil || err.Error() != want {
+		t.Errorf("wanted error to be %v, got %v instead", want, err)
+	}
+	if code := ExitCode(err); code != ExitSoftware {
+		t.Errorf("wanted exit code %d, got %d instead", ExitSoftware, code)
+	}
+
+	m := regexp.MustCompile(`A crash report was saved to (.+)\n`).FindStringSubmatch(stderr.String())
+	if m == nil {
+		t.Fatalf("crash report location not found on error output: %v", stderr.String())
+	}
+	if !strings.HasPrefix(stderr.String(), m[0]) {
+		t.Errorf("unexpected error output: %v", stderr.String())
+	}
+	stderr.Reset()
+	p.PrintError(err)
+	if want := "Error: panic: something went wrong\n"; stderr.String() != want {
+		t.Errorf("got error output %q, wanted %q", stderr.String(), want)
+	}
+	bs, err := ioutil.ReadFile(m[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := string(bs)
+	for _, want := range []string{
+		"Command: panic\n",
+		`Arguments: ["-token" "[REDACTED]" "arg"]` + "\n",
+		"Version: 1.2.3\n",
+		"Panic: something went wrong\n",
+		"panicCommand",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("wanted crash report to contain %q, got %v instead", want, report)
+		}
+	}
+	if strings.Contains(report, "s3cr3t") {
+		t.Errorf("crash report leaks secret: %v", report)
+	}
+}
+
+func TestProgramPanicsWithoutRecovery(t *testing.T) {
+	defer func() {
+		if r := recover(); r != "something went wrong" {
+			t.Errorf("expected panic message not found, got %v instead", r)
+		}
+	}()
+	p := Program{
+		Root: &panicCommand{},
+	}
+	t.Fatal(p.Run(context.Background()))
+}
+
+func TestRedactArgs(t *testing.T) {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.Bool("auth", false, "")
+	testCases := []struct {
+		in   []string
+		want []string
+	}{
+		{
+			in:   []string{"-name", "Gopher", "x"},
+			want: []string{"-name", "Gopher", "x"},
+		},
+		{
+			in:   []string{"-password", "p", "--api-key=k", "-token="},
+			want: []string{"-password", redacted, "--api-key=" + redacted, "-token=" + redacted},
+		},
+		{
+			in:   []string{"-auth", "x", "-secret"},
+			want: []string{"-auth", "x", "-secret"},
+		},
+		{
+			in:   []string{"x", "--", "-token", "t"},
+			want: []string{"x", "--", "-token", "t"},
+		},
+	}
+	for _, tc := range testCases {
+		if got := redactArgs(tc.in, fs); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("wanted redactArgs(%q) = %q, got %q instead", tc.in, tc.want, got)
+		}
+	}
+}
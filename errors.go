@@ -0,0 +1,226 @@
+package clino
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// Hinter errors contain a hint to help users fix the problem, like a command they should run next.
+//
+// Program.PrintError prints the hint after the error message.
+type Hinter interface {
+	Hint() string
+}
+
+// DetailedError is an error with a detailed explanation and a suggested next command.
+// 	return clino.DetailedError{
+// 		Title:      "not logged in",
+// 		Detail:     "You need to log in to deploy your application.",
+// 		Suggestion: "app login",
+// 	}
+type DetailedError struct {
+	// Title is a short description of the error.
+	Title string
+
+	// Detail explains the error and how to fix it.
+	Detail string
+
+	// Suggestion of a command the user should run next.
+	Suggestion string
+
+	// Err is the underlying error, if any.
+	Err error
+}
+
+// Error returns the title of the error, followed by the underlying error message.
+func (de DetailedError) Error() string {
+	switch {
+	case de.Err == nil:
+		return de.Title
+	case de.Title == "":
+		return de.Err.Error()
+	}
+	return fmt.Sprintf("%s: %v", de.Title, de.Err)
+}
+
+// Unwrap error.
+func (de DetailedError) Unwrap() error { return de.Err }
+
+// Hint containing the detail and suggestion of the error.
+func (de DetailedError) Hint() string {
+	var lines []string
+	if de.Detail != "" {
+		lines = append(lines, de.Detail)
+	}
+	if de.Suggestion != "" {
+		lines = append(lines, fmt.Sprintf("Try running '%s'.", de.Suggestion))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// PrintError to the error output of the program in a consistent format.
+//
+// The error message is followed by its hint, if it implements the Hinter interface,
+// and by a pointer to the help of the command on usage errors.
+// If the JSON output format is set (see Program.OutputFlag), the error is printed as a JSON object instead.
+// You should call it with the error returned by Run.
+// 	if err := p.Run(context.Background(), os.Args[1:]...); err != nil {
+// 		p.PrintError(err)
+// 		os.Exit(clino.ExitCode(err))
+// 	}
+func (p *Program) PrintError(err error) {
+	if err == nil {
+		return
+	}
+	w := p.ErrOutput
+	if w == nil {
+		w = os.Stderr
+	}
+	if p.output == outputJSON {
+		p.printErrorJSON(w, err)
+		return
+	}
+	fmt.Fprintf(w, "Error: %v\n", err)
+	if hint := errorHint(err); hint != "" {
+		fmt.Fprintf(w, "Hint: %s\n", strings.Replace(hint, "\n", "\n      ", -1))
+	}
+	if ExitCode(err) == ExitUsage && p.Root != nil {
+		fmt.Fprintf(w, "Run '%s' for usage.\n", strings.Join(p.helpCommand(), " "))
+	}
+}
+
+// errorHint returns the hint of the first error implementing the Hinter interface in the chain.
+func errorHint(err error) string {
+	var h Hinter
+	if errors.As(err, &h) {
+		return strings.TrimSpace(h.Hint())
+	}
+	return ""
+}
+
+// helpCommand to get help for the last command run.
+func (p *Program) helpCommand() []string {
+	cmd := []string{p.Root.Name(), "help"}
+	if len(p.trail) > 1 {
+		for _, c := range p.trail[1:] {
+			cmd = append(cmd, c.Name())
+		}
+	}
+	return cmd
+}
+
+// jsonError is the format of errors printed when using the JSON output format.
+type jsonError struct {
+	Code       int      `json:"code"`
+	Message    string   `json:"message"`
+	Command    []string `json:"command,omitempty"`
+	Hint       string   `json:"hint,omitempty"`
+	Title      string   `json:"title,omitempty"`
+	Detail     string   `json:"detail,omitempty"`
+	Suggestion string   `json:"suggestion,omitempty"`
+	Usage      string   `json:"usage,omitempty"`
+}
+
+func (p *Program) printErrorJSON(w io.Writer, err error) {
+	je := jsonError{
+		Code:    ExitCode(err),
+		Message: err.Error(),
+		Hint:    errorHint(err),
+	}
+	for _, c := range p.trail {
+		je.Command = append(je.Command, c.Name())
+	}
+	var de DetailedError
+	if errors.As(err, &de) {
+		je.Title, je.Detail, je.Suggestion = de.Title, de.Detail, de.Suggestion
+	}
+	if je.Code == ExitUsage && p.Root != nil {
+		je.Usage = strings.Join(p.helpCommand(), " ")
+	}
+	if jerr := json.NewEncoder(w).Encode(je); jerr != nil {
+		fmt.Fprintf(w, "Error: %v\n", err)
+	}
+}
+
+// Output formats for Program.OutputFlag.
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// outputFormat is the value of the -output flag.
+type outputFormat string
+
+func (o *outputFormat) String() string { return string(*o) }
+
+func (o *outputFormat) Set(v string) error {
+	if v != outputText && v != outputJSON {
+		return fmt.Errorf("must be %s or %s", outputText, outputJSON)
+	}
+	*o = outputFormat(v)
+	return nil
+}
+
+// setOutputFormat adds the -output flag, unless the command or global flags already define one.
+// In that case, the output format is only read from the environment.
+func (p *Program) setOutputFormat(trail []Command) {
+	if !p.builtinFlag(trail, "output") {
+		p.output = p.envOutputFormat()
+		return
+	}
+	p.fs.Var((*outputFormat)(&p.output), "output", "output format for errors: text or json")
+}
+
+// scanOutputFormat reads the output format from the environment and arguments, without parsing them.
+// The arguments are scanned before parsing the flags so the format is known even if parsing fails.
+func (p *Program) scanOutputFormat(args []string) string {
+	output := p.envOutputFormat()
+	if v, ok := scanFlag(args, "output"); ok && (v == outputText || v == outputJSON) {
+		output = v
+	}
+	return output
+}
+
+// envOutputFormat reads the output format from the environment.
+func (p *Program) envOutputFormat() string {
+	if v := os.Getenv(p.envName("OUTPUT")); v == outputJSON {
+		return v
+	}
+	return outputText
+}
+
+// envName returns the name of the environment variable with the given suffix for the program,
+// such as APP_OUTPUT for the "app" root command.
+func (p *Program) envName(suffix string) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, p.Root.Name())
+	return name + "_" + suffix
+}
+
+// scanFlag looks for the value of a flag in the arguments without parsing them.
+func scanFlag(args []string, name string) (value string, ok bool) {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if arg != "-"+name && arg != "--"+name {
+			if v := strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-"); strings.HasPrefix(v, name+"=") {
+				value, ok = v[len(name)+1:], true
+			}
+			continue
+		}
+		if i+1 < len(args) {
+			value, ok = args[i+1], true
+		}
+	}
+	return value, ok
+}
@@ -0,0 +1,273 @@
+package clino
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetailedError(t *testing.T) {
+	errNetwork := errors.New("connection refused")
+	testCases := []struct {
+		desc string
+		in   DetailedError
+		err  string
+		hint string
+	}{
+		{
+			desc: "title",
+			in:   DetailedError{Title: "not logged in"},
+			err:  "not logged in",
+		},
+		{
+			desc: "error",
+			in:   DetailedError{Err: errNetwork},
+			err:  "connection refused",
+		},
+		{
+			desc: "title, error, detail, and suggestion",
+			in: DetailedError{
+				Title:      "cannot deploy",
+				Detail:     "The server is unreachable.",
+				Suggestion: "app status",
+				Err:        errNetwork,
+			},
+			err:  "cannot deploy: connection refused",
+			hint: "The server is unreachable.\nTry running 'app status'.",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := tc.in.Error(); got != tc.err {
+				t.Errorf("wanted error message %q, got %q instead", tc.err, got)
+			}
+			if got := tc.in.Hint(); got != tc.hint {
+				t.Errorf("wanted hint %q, got %q instead", tc.hint, got)
+			}
+			if tc.in.Unwrap() != tc.in.Err {
+				t.Errorf("expected unwrapped error to be %v", tc.in.Err)
+			}
+		})
+	}
+}
+
+func TestProgramPrintError(t *testing.T) {
+	testCases := []struct {
+		desc string
+		root Command
+		args []string
+		want string
+	}{
+		{
+			desc: "command not found",
+			root: &rootCommand{},
+			args: []string{"notfound"},
+			want: "Error: unknown command: 'app notfound'\nRun 'app help' for usage.\n",
+		},
+		{
+			desc: "undefined flag on inner command",
+			root: &rootCommandWithFlags{},
+			args: []string{"inner", "simple", "-undefined"},
+			want: "Error: flag provided but not defined: -undefined\nRun 'cmd help inner simple' for usage.\n",
+		},
+		{
+			desc: "command error",
+			root: &failCommand{err: errors.New("something went wrong")},
+			want: "Error: something went wrong\n",
+		},
+		{
+			desc: "command error with hint",
+			root: &failCommand{
+				err: ConfigError(DetailedError{
+					Title:      "not logged in",
+					Detail:     "You need to log in to deploy your application.\nYour session might have expired.",
+					Suggestion: "app login",
+				}),
+			},
+			want: `Error: not logged in
+Hint: You need to log in to deploy your application.
+      Your session might have expired.
+      Try running 'app login'.
+`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var stderr bytes.Buffer
+			p := Program{
+				Root:      tc.root,
+				Output:    ioutil.Discard,
+				ErrOutput: &stderr,
+			}
+			p.PrintError(p.Run(context.Background(), tc.args...))
+			if got := stderr.String(); got != tc.want {
+				t.Errorf("got error output %q, wanted %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestProgramPrintErrorReused(t *testing.T) {
+	config := &defaultParentCommand{name: "config", def: "show", children: []Command{
+		&treeCommand{name: "show"},
+		&treeCommand{name: "set"},
+		&treeCommand{name: "sync"},
+	}}
+	var stderr bytes.Buffer
+	p := Program{
+		Root:           &defaultParentCommand{name: "app", children: []Command{config}},
+		Output:         ioutil.Discard,
+		ErrOutput:      &stderr,
+		PrefixMatching: true,
+	}
+	runs := []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"config", "show"}},
+		{
+			args: []string{"config", "s"},
+			want: "Error: ambiguous command: 'app config s' could be show, set, sync\nRun 'app help' for usage.\n",
+		},
+		{args: []string{"config", "set"}},
+		{
+			args: []string{"config", "delte"},
+			want: "Error: unknown command: 'app config delte'\nRun 'app help config' for usage.\n",
+		},
+	}
+	for _, r := range runs {
+		stderr.Reset()
+		p.PrintError(p.Run(context.Background(), r.args...))
+		if got := stderr.String(); got != r.want {
+			t.Errorf("running %q: got error output %q, wanted %q", r.args, got, r.want)
+		}
+	}
+}
+
+func TestProgramPrintErrorNil(t *testing.T) {
+	var stderr bytes.Buffer
+	p := Program{ErrOutput: &stderr}
+	p.PrintError(nil)
+	if stderr.Len() != 0 {
+		t.Errorf("got unexpected error output, should be empty: %v", stderr.String())
+	}
+}
+
+func TestProgramPrintErrorJSON(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		root    Command
+		env     string
+		aliases string
+		args    []string
+		want    string
+	}{
+		{
+			desc: "command not found",
+			root: &rootCommand{},
+			args: []string{"notfound", "-output=json"},
+			want: `{"code":64,"message":"unknown command: 'app notfound'","command":["app"],"usage":"app help"}` + "\n",
+		},
+		{
+			desc: "undefined flag",
+			root: &rootCommandWithFlags{},
+			args: []string{"inner", "simple", "-undefined", "-output", "json"},
+			want: `{"code":64,"message":"flag provided but not defined: -undefined","command":["cmd","inner","simple"],"usage":"cmd help inner simple"}` + "\n",
+		},
+		{
+			desc: "detailed error from environment variable",
+			root: &failCommand{
+				err: DetailedError{
+					Title:      "not logged in",
+					Detail:     "You need to log in to deploy your application.",
+					Suggestion: "app login",
+				},
+			},
+			env:  "json",
+			want: `{"code":1,"message":"not logged in","command":["fail"],"hint":"You need to log in to deploy your application.\nTry running 'app login'.","title":"not logged in","detail":"You need to log in to deploy your application.","suggestion":"app login"}` + "\n",
+		},
+		{
+			desc: "text flag overrides environment variable",
+			root: &failCommand{err: errors.New("something went wrong")},
+			env:  "json",
+			args: []string{"-output", "text"},
+			want: "Error: something went wrong\n",
+		},
+		{
+			desc: "invalid output format",
+			root: &failCommand{err: errors.New("something went wrong")},
+			args: []string{"-output=xml"},
+			want: "Error: invalid value \"xml\" for flag -output: must be text or json\nRun 'fail help' for usage.\n",
+		},
+		{
+			desc: "arguments file error",
+			root: &failCommand{err: errors.New("something went wrong")},
+			args: []string{"-output=json", "@" + filepath.Join("testdata", "missing-args")},
+			want: `{"code":66,"message":"cannot read arguments: open ` + filepath.Join("testdata", "missing-args") + `: `,
+		},
+		{
+			desc:    "alias error",
+			root:    &failCommand{err: errors.New("something went wrong")},
+			env:     "json",
+			aliases: "bad = 'unterminated",
+			want:    `{"code":78,"message":"invalid alias on $FAIL_ALIASES:1: alias bad: unterminated quoted string"}` + "\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			defer os.Setenv("FAIL_OUTPUT", os.Getenv("FAIL_OUTPUT"))
+			if err := os.Setenv("FAIL_OUTPUT", tc.env); err != nil {
+				t.Fatal(err)
+			}
+			defer os.Setenv("FAIL_ALIASES", os.Getenv("FAIL_ALIASES"))
+			if err := os.Setenv("FAIL_ALIASES", tc.aliases); err != nil {
+				t.Fatal(err)
+			}
+			var stderr bytes.Buffer
+			p := Program{
+				Root:        tc.root,
+				Output:      ioutil.Discard,
+				ErrOutput:   &stderr,
+				OutputFlag:  true,
+				ArgsFiles:   true,
+				UserAliases: true,
+			}
+			p.PrintError(p.Run(context.Background(), tc.args...))
+			// errors from the operating system are only compared by prefix.
+			if got := stderr.String(); got != tc.want && (strings.HasSuffix(tc.want, "\n") || !strings.HasPrefix(got, tc.want)) {
+				t.Errorf("got error output %q, wanted %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestScanFlag(t *testing.T) {
+	testCases := []struct {
+		args  []string
+		value string
+		ok    bool
+	}{
+		{args: []string{"a", "-output", "json"}, value: "json", ok: true},
+		{args: []string{"--output=json", "b"}, value: "json", ok: true},
+		{args: []string{"-output"}},
+		{args: []string{"-outputs=json"}},
+		{args: []string{"--", "-output=json"}},
+	}
+	for _, tc := range testCases {
+		if value, ok := scanFlag(tc.args, "output"); value != tc.value || ok != tc.ok {
+			t.Errorf("wanted scanFlag(%q) = (%q, %v), got (%q, %v) instead", tc.args, tc.value, tc.ok, value, ok)
+		}
+	}
+}
+
+func TestProgramEnvName(t *testing.T) {
+	p := Program{Root: &namedCommand{name: "my-app.v2"}}
+	if got, want := p.envName("OUTPUT"), "MY_APP_V2_OUTPUT"; got != want {
+		t.Errorf("wanted environment variable name %q, got %q instead", want, got)
+	}
+}
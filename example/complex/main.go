@@ -86,10 +86,11 @@ type HelloCommand struct {
 
 // Run command.
 func (hc *HelloCommand) Run(ctx context.Context, args ...string) error {
+	streams := clino.IO(ctx)
 	if hc.State.Verbose {
-		fmt.Println("Starting command...")
+		fmt.Fprintln(streams.Err, "Starting command...")
 	}
-	fmt.Printf("Hello, %s!\n", hc.name)
+	fmt.Fprintf(streams.Out, "Hello, %s!\n", hc.name)
 	return nil
 }
 
@@ -116,7 +117,7 @@ func main() {
 		},
 	}
 	if err := p.Run(context.Background(), os.Args[1:]...); err != nil {
-		fmt.Fprintf(os.Stderr, "%+v\n", err)
+		p.PrintError(err)
 		os.Exit(clino.ExitCode(err))
 	}
 }
@@ -15,7 +15,7 @@ func main() {
 		Root: rc,
 	}
 	if err := p.Run(context.Background(), os.Args[1:]...); err != nil {
-		fmt.Fprintf(os.Stderr, "%+v\n", err)
+		p.PrintError(err)
 		os.Exit(clino.ExitCode(err))
 	}
 }
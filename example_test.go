@@ -40,7 +40,7 @@ func Example() {
 		Root: &RootCommand{},
 	}
 	if err := p.Run(context.Background(), "-name", "Gopher"); err != nil {
-		fmt.Fprintf(os.Stderr, "%+v\n", err)
+		p.PrintError(err)
 		os.Exit(clino.ExitCode(err))
 	}
 	// Output:
@@ -1,12 +1,80 @@
 package clino
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"syscall"
 )
 
+// Exit codes for common errors, following the conventions of sysexits.h.
+// They are stable, so scripts can rely on them.
+const (
+	// ExitUsage means the command was used incorrectly (EX_USAGE).
+	// Program uses it for unknown commands and flags.
+	ExitUsage = 64
+
+	// ExitDataErr means the input data was incorrect (EX_DATAERR).
+	ExitDataErr = 65
+
+	// ExitNoInput means an input file didn't exist or wasn't readable (EX_NOINPUT).
+	ExitNoInput = 66
+
+	// ExitNoUser means the user specified didn't exist (EX_NOUSER).
+	ExitNoUser = 67
+
+	// ExitNoHost means the host specified didn't exist (EX_NOHOST).
+	ExitNoHost = 68
+
+	// ExitUnavailable means a service is unavailable (EX_UNAVAILABLE).
+	ExitUnavailable = 69
+
+	// ExitSoftware means an internal software error (EX_SOFTWARE).
+	// Program uses it when a command panics and Program.RecoverPanics is set.
+	ExitSoftware = 70
+
+	// ExitOSErr means an operating system error (EX_OSERR).
+	ExitOSErr = 71
+
+	// ExitOSFile means a system file didn't exist or had an error (EX_OSFILE).
+	ExitOSFile = 72
+
+	// ExitCantCreate means an output file couldn't be created (EX_CANTCREAT).
+	ExitCantCreate = 73
+
+	// ExitIOErr means an error happened while doing I/O on a file (EX_IOERR).
+	ExitIOErr = 74
+
+	// ExitTempFail means a temporary failure, and the user is invited to retry (EX_TEMPFAIL).
+	ExitTempFail = 75
+
+	// ExitProtocol means the remote system returned something invalid during a protocol exchange (EX_PROTOCOL).
+	ExitProtocol = 76
+
+	// ExitNoPerm means the user doesn't have sufficient permission to perform the operation (EX_NOPERM).
+	ExitNoPerm = 77
+
+	// ExitConfig means something was found in an unconfigured or misconfigured state (EX_CONFIG).
+	ExitConfig = 78
+
+	// ExitTimeout means the command ran out of time, like timeout(1) does.
+	// It is the default exit code for context.DeadlineExceeded errors.
+	ExitTimeout = 124
+
+	// ExitCanceled means the command was canceled, like when interrupted by SIGINT (128+2).
+	// It is the default exit code for context.Canceled errors.
+	ExitCanceled = 130
+)
+
+// ExitCoder is implemented by errors carrying their own exit code.
+//
+// Implement it on your domain errors to use a specific exit code without wrapping them with ExitError.
+type ExitCoder interface {
+	ExitCode() int
+}
+
 // ExitError wraps the error, adding an exit code.
 //
 // You can use it to exit the process gracefully with a specific exit code when something goes wrong.
@@ -24,17 +92,58 @@ func (ee ExitError) Error() string {
 // Unwrap error.
 func (ee ExitError) Unwrap() error { return ee.Err }
 
+// ExitCode of the error.
+func (ee ExitError) ExitCode() int { return ee.Code }
+
+func newExitError(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return ExitError{
+		Code: code,
+		Err:  err,
+	}
+}
+
+// UsageError wraps the error with the ExitUsage exit code. It returns nil if err is nil.
+func UsageError(err error) error { return newExitError(ExitUsage, err) }
+
+// DataError wraps the error with the ExitDataErr exit code. It returns nil if err is nil.
+func DataError(err error) error { return newExitError(ExitDataErr, err) }
+
+// NoInputError wraps the error with the ExitNoInput exit code. It returns nil if err is nil.
+func NoInputError(err error) error { return newExitError(ExitNoInput, err) }
+
+// UnavailableError wraps the error with the ExitUnavailable exit code. It returns nil if err is nil.
+func UnavailableError(err error) error { return newExitError(ExitUnavailable, err) }
+
+// SoftwareError wraps the error with the ExitSoftware exit code. It returns nil if err is nil.
+func SoftwareError(err error) error { return newExitError(ExitSoftware, err) }
+
+// TempFailError wraps the error with the ExitTempFail exit code. It returns nil if err is nil.
+func TempFailError(err error) error { return newExitError(ExitTempFail, err) }
+
+// NoPermError wraps the error with the ExitNoPerm exit code. It returns nil if err is nil.
+func NoPermError(err error) error { return newExitError(ExitNoPerm, err) }
+
+// ConfigError wraps the error with the ExitConfig exit code. It returns nil if err is nil.
+func ConfigError(err error) error { return newExitError(ExitConfig, err) }
+
 // ExitCode from the command for the process to use when exiting.
 // It returns 0 if the error is nil.
 // If the error comes from *exec.Cmd Run, the same child process exit code
 // is used. If the error is ExitError, it returns the Code field.
+// If the child process was terminated by a signal, it returns 128+signal, like shells do.
+// If the error implements ExitCoder, it returns its ExitCode.
+// If the error is context.Canceled or context.DeadlineExceeded, it returns ExitCanceled or ExitTimeout.
+// The error chain is inspected from the outermost error to the innermost one.
 // Otherwise, return exit code 1.
 // 	func main() {
 //		p := clino.Program{
 // 			Root: &RootCommand{},
 // 		}
 // 		if err := p.Run(context.Background(), os.Args[1:]...); err != nil {
-// 			fmt.Fprintf(os.Stderr, "%+v\n", err)
+// 			p.PrintError(err)
 // 			os.Exit(clino.ExitCode(err))
 // 		}
 // 	}
@@ -44,17 +153,49 @@ func ExitCode(err error) int {
 		return 0
 	}
 
-	var ee ExitError
-	if errors.As(err, &ee) {
-		return ee.Code
+	for ; err != nil; err = errors.Unwrap(err) {
+		switch e := err.(type) {
+		case *exec.ExitError:
+			// *exec.ExitError implements ExitCoder, but returns -1 when the process wasn't exited.
+			if ws, ok := e.Sys().(syscall.WaitStatus); ok {
+				switch {
+				case ws.Exited():
+					return ws.ExitStatus()
+				case ws.Signaled():
+					return signalExitCode(ws.Signal())
+				}
+			}
+		case ExitCoder:
+			return e.ExitCode()
+		}
+		switch err {
+		case context.Canceled:
+			return ExitCanceled
+		case context.DeadlineExceeded:
+			return ExitTimeout
+		}
 	}
+	return 1
+}
 
-	var xe *exec.ExitError
-	if errors.As(err, &xe) {
-		if ws, ok := xe.Sys().(syscall.WaitStatus); ok && ws.Exited() {
-			return ws.ExitStatus()
+// contextExitCause returns context.Canceled or context.DeadlineExceeded
+// if the exit code of the error is determined by one of them, or nil otherwise.
+func contextExitCause(err error) error {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if err == context.Canceled || err == context.DeadlineExceeded {
+			return err
+		}
+		if _, ok := err.(ExitCoder); ok {
+			return nil
 		}
 	}
+	return nil
+}
 
+// signalExitCode returns the conventional shell exit code for a process terminated by a signal.
+func signalExitCode(sig os.Signal) int {
+	if s, ok := sig.(syscall.Signal); ok {
+		return 128 + int(s)
+	}
 	return 1
 }
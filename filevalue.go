@@ -0,0 +1,116 @@
+package clino
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+)
+
+// FileValue wraps the flag value so users can also read it from a file with @path, or from the standard input with -.
+// This keeps secrets and large payloads out of the process list and shell history.
+// A single trailing newline is removed from the value read.
+// 	flags.Var(clino.FileValue(&body), "body", "request body")
+//
+// Only one flag can read from the standard input, which is the Program input when running commands.
+// If Program.ArgsFiles is set, users must pass files as -flag=@path, otherwise the argument is expanded.
+func FileValue(v flag.Value) flag.Value {
+	return &fileValue{Value: v}
+}
+
+// StringFileVar defines a string flag with the specified name, default value, and usage string,
+// whose value can also be read from a file with @path, or from the standard input with -.
+// The argument p points to a string variable in which to store the value of the flag.
+// 	clino.StringFileVar(flags, &c.token, "token", "", "API token")
+func StringFileVar(flags *flag.FlagSet, p *string, name, value, usage string) {
+	var fs flag.FlagSet
+	fs.StringVar(p, name, value, usage)
+	flags.Var(FileValue(fs.Lookup(name).Value), name, usage)
+}
+
+// fileValue is a flag value that can be read from a file or the standard input.
+type fileValue struct {
+	flag.Value
+	stdin *stdinReader
+}
+
+// String returns the value of the wrapped flag.
+func (fv *fileValue) String() string {
+	if fv.Value == nil {
+		return ""
+	}
+	return fv.Value.String()
+}
+
+// Set the value of the wrapped flag, reading it from a file or the standard input if requested.
+func (fv *fileValue) Set(s string) error {
+	var (
+		b   []byte
+		err error
+	)
+	switch {
+	case s == "-":
+		b, err = fv.stdin.read()
+	case len(s) > 1 && s[0] == '@':
+		b, err = ioutil.ReadFile(s[1:])
+	default:
+		return fv.Value.Set(s)
+	}
+	if err != nil {
+		return err
+	}
+	v := strings.TrimSuffix(string(b), "\n")
+	return fv.Value.Set(strings.TrimSuffix(v, "\r"))
+}
+
+// IsBoolFlag reports whether the wrapped flag is a boolean flag.
+func (fv *fileValue) IsBoolFlag() bool {
+	b, ok := fv.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// Get the value of the wrapped flag, if it implements flag.Getter.
+func (fv *fileValue) Get() interface{} {
+	if g, ok := fv.Value.(flag.Getter); ok {
+		return g.Get()
+	}
+	return fv.String()
+}
+
+// stdinReader lets a single flag read the standard input.
+type stdinReader struct {
+	r io.Reader
+
+	mu   sync.Mutex
+	done bool
+}
+
+// read the standard input. It fails if it was already read.
+func (sr *stdinReader) read() ([]byte, error) {
+	if sr == nil {
+		return ioutil.ReadAll(os.Stdin)
+	}
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	if sr.done {
+		return nil, errors.New("standard input already read by another flag")
+	}
+	sr.done = true
+	return ioutil.ReadAll(sr.r)
+}
+
+// setFileValuesInput makes the flags wrapped by FileValue read the standard input from r.
+func setFileValuesInput(fs *flag.FlagSet, r io.Reader) {
+	stdin := &stdinReader{r: r}
+	fs.VisitAll(func(f *flag.Flag) {
+		if fv, ok := f.Value.(*fileValue); ok {
+			fv.stdin = stdin
+		}
+	})
+}
+
+// fileValueUsage is appended to the usage of flags wrapped by FileValue.
+const fileValueUsage = " (use @file to read it from a file, or - for the standard input)"
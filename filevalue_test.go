@@ -0,0 +1,125 @@
+package clino
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileValue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clino-filevalue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"token": "secret\n",
+		"body":  "line 1\nline 2\r\n\n",
+		"count": "3",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	at := func(name string) string {
+		return "@" + filepath.Join(dir, name)
+	}
+
+	testCases := []struct {
+		desc      string
+		args      []string
+		want      fileFlagsCommand
+		wantErr   string
+		wantInput string
+	}{
+		{
+			desc: "defaults",
+			want: fileFlagsCommand{body: "{}", count: 1},
+		},
+		{
+			desc: "values",
+			args: []string{"-token", "plain", "-body", "@", "-count=2"},
+			want: fileFlagsCommand{token: "plain", body: "@", count: 2},
+		},
+		{
+			desc: "files",
+			args: []string{"-token", at("token"), "-body=" + at("body"), "-count", at("count")},
+			want: fileFlagsCommand{token: "secret", body: "line 1\nline 2\r\n", count: 3},
+		},
+		{
+			desc: "standard input",
+			args: []string{"-body", "-"},
+			want: fileFlagsCommand{body: "from input", count: 1},
+		},
+		{
+			desc:    "standard input read twice",
+			args:    []string{"-token", "-", "-body", "-"},
+			wantErr: `invalid value "-" for flag -body: standard input already read by another flag`,
+		},
+		{
+			desc:    "missing file",
+			args:    []string{"-token", at("missing")},
+			wantErr: `invalid value "` + at("missing") + `" for flag -token: open ` + filepath.Join(dir, "missing") + ": ",
+		},
+		{
+			desc:    "invalid value",
+			args:    []string{"-count", at("token")},
+			wantErr: `invalid value "` + at("token") + `" for flag -count: parse error`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			cmd := &fileFlagsCommand{}
+			p := Program{
+				Root:   cmd,
+				Input:  strings.NewReader("from input\n"),
+				Output: ioutil.Discard,
+			}
+			err := p.Run(context.Background(), tc.args...)
+			if tc.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tc.wantErr) {
+					t.Errorf("wanted error %q, got %v instead", tc.wantErr, err)
+				}
+				if code := ExitCode(err); code != ExitUsage {
+					t.Errorf("wanted exit code %d, got %d instead", ExitUsage, code)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("wanted error to be nil, got %v instead", err)
+			}
+			if *cmd != tc.want {
+				t.Errorf("got flags %+v, wanted %+v", *cmd, tc.want)
+			}
+		})
+	}
+}
+
+func TestFileValueHelp(t *testing.T) {
+	var buf bytes.Buffer
+	p := Program{
+		Root:   &fileFlagsCommand{},
+		Output: &buf,
+	}
+	if err := p.Run(context.Background(), "-help"); err != nil {
+		t.Errorf("wanted error to be nil, got %v instead", err)
+	}
+	const golden = "testdata/file_value_help.golden"
+	if *update {
+		if err := ioutil.WriteFile(golden, buf.Bytes(), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bs, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("opening %s: %v", golden, err)
+	}
+	if got := buf.String(); got != string(bs) {
+		t.Errorf("got output %v\n, wanted %v", got, string(bs))
+	}
+}
@@ -0,0 +1,91 @@
+package clino
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// commandFlags collects the flags of the commands on the trail before adding them to the program flag set,
+// resolving conflicts between flags with the same name.
+type commandFlags struct {
+	flags []*flag.Flag
+
+	// owners of the flags: the path of the command defining each flag, or "" for global and built-in flags.
+	owners map[string]string
+
+	// shadow flags of ancestors instead of failing on conflicts.
+	shadow bool
+}
+
+// newCommandFlags creates a commandFlags with the flags already defined on fs as global flags.
+func newCommandFlags(fs *flag.FlagSet, shadow bool) *commandFlags {
+	cf := &commandFlags{
+		owners: map[string]string{},
+		shadow: shadow,
+	}
+	fs.VisitAll(func(f *flag.Flag) {
+		cf.owners[f.Name] = ""
+	})
+	return cf
+}
+
+// define the flags of the command with the given path.
+// It fails if a flag is already defined, unless shadowing is enabled and the flag isn't global.
+func (cf *commandFlags) define(kind string, trail []Command, define func(*flag.FlagSet)) error {
+	var err error
+	path := commandPath(trail)
+	fs := flag.NewFlagSet(path, flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	define(fs)
+	fs.VisitAll(func(f *flag.Flag) {
+		owner, ok := cf.owners[f.Name]
+		if ok && (owner == "" || !cf.shadow) {
+			if err == nil {
+				err = SoftwareError(errors.New(flagConflict(kind, f.Name, path, owner)))
+			}
+			return
+		}
+		cf.owners[f.Name] = path
+		if !ok {
+			cf.flags = append(cf.flags, f)
+			return
+		}
+		for i, prev := range cf.flags {
+			if prev.Name == f.Name {
+				cf.flags[i] = f
+			}
+		}
+	})
+	return err
+}
+
+// apply adds the collected flags to fs.
+func (cf *commandFlags) apply(fs *flag.FlagSet) {
+	for _, f := range cf.flags {
+		fs.Var(f.Value, f.Name, f.Usage)
+	}
+}
+
+// flagConflict describes a flag defined by a command when its name is already used by the owner.
+// An empty owner means a global or built-in flag.
+func flagConflict(kind, name, path, owner string) string {
+	switch owner {
+	case "":
+		return fmt.Sprintf("%s -%s of command '%s' is already defined as a global or built-in flag", kind, name, path)
+	case path:
+		return fmt.Sprintf("%s -%s of command '%s' is already defined as a persistent flag", kind, name, path)
+	}
+	return fmt.Sprintf("%s -%s of command '%s' is already defined by command '%s'", kind, name, path, owner)
+}
+
+// commandPath returns the names of the commands on the trail, separated by spaces.
+func commandPath(trail []Command) string {
+	names := make([]string, len(trail))
+	for i, c := range trail {
+		names[i] = c.Name()
+	}
+	return strings.Join(names, " ")
+}
@@ -0,0 +1,139 @@
+package clino
+
+import (
+	"context"
+	"flag"
+	"io/ioutil"
+	"testing"
+)
+
+func TestFlagConflicts(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		shadow    bool
+		global    bool
+		args      []string
+		want      string
+		wantChild bool
+	}{
+		{
+			desc: "conflict",
+			args: []string{"child", "-verbose"},
+			want: "flag -verbose of command 'app child' is already defined by command 'app'",
+		},
+		{
+			desc: "conflict on help",
+			args: []string{"help", "child"},
+			want: "flag -verbose of command 'app child' is already defined by command 'app'",
+		},
+		{
+			desc:      "shadowing",
+			shadow:    true,
+			args:      []string{"child", "-verbose"},
+			wantChild: true,
+		},
+		{
+			desc:   "global flags cannot be shadowed",
+			shadow: true,
+			global: true,
+			args:   []string{"child", "-verbose"},
+			want:   "persistent flag -verbose of command 'app' is already defined as a global or built-in flag",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			child := &treeCommand{name: "child", flags: []string{"verbose"}}
+			root := &treeCommand{name: "app", persistent: []string{"verbose"}, children: []Command{child}}
+			p := Program{
+				Root:               root,
+				Output:             ioutil.Discard,
+				AllowFlagShadowing: tc.shadow,
+			}
+			if tc.global {
+				p.GlobalFlags = func(flags *flag.FlagSet) {
+					flags.Bool("verbose", false, "")
+				}
+			}
+			err := p.Run(context.Background(), tc.args...)
+			if tc.want != "" {
+				if err == nil || err.Error() != tc.want {
+					t.Fatalf("wanted error to be %q, got %v instead", tc.want, err)
+				}
+				if code := ExitCode(err); code != ExitSoftware {
+					t.Errorf("wanted exit code %d, got %d instead", ExitSoftware, code)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("wanted error to be nil, got %v instead", err)
+			}
+			if *root.values["verbose"] {
+				t.Error("wanted persistent flag of the root command to be shadowed")
+			}
+			if got := *child.values["verbose"]; got != tc.wantChild {
+				t.Errorf("wanted flag of the child command to be %v, got %v instead", tc.wantChild, got)
+			}
+		})
+	}
+}
+
+func TestProgramBuiltinFlagsDefined(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		program Program
+		name    string
+	}{
+		{
+			desc:    "timeout",
+			program: Program{TimeoutFlag: true},
+			name:    "timeout",
+		},
+		{
+			desc:    "output",
+			program: Program{OutputFlag: true},
+			name:    "output",
+		},
+		{
+			desc:    "format",
+			program: Program{FormatFlag: true},
+			name:    "format",
+		},
+		{
+			desc:    "yes",
+			program: Program{PromptFlags: true},
+			name:    "yes",
+		},
+		{
+			desc:    "no-input",
+			program: Program{PromptFlags: true},
+			name:    "no-input",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc+" command flag", func(t *testing.T) {
+			child := &treeCommand{name: "child", flags: []string{tc.name}}
+			p := tc.program
+			p.Root, p.Output = &treeCommand{name: "app", children: []Command{child}}, ioutil.Discard
+			if err := p.Run(context.Background(), "child", "-"+tc.name); err != nil {
+				t.Fatalf("wanted error to be nil, got %v instead", err)
+			}
+			if !*child.values[tc.name] {
+				t.Errorf("wanted -%s flag of the command to be set", tc.name)
+			}
+		})
+		t.Run(tc.desc+" global flag", func(t *testing.T) {
+			var global *bool
+			p := tc.program
+			p.Root, p.Output = &treeCommand{name: "app"}, ioutil.Discard
+			p.GlobalFlags = func(flags *flag.FlagSet) {
+				global = flags.Bool(tc.name, false, "")
+			}
+			if err := p.Run(context.Background(), "-"+tc.name); err != nil {
+				t.Fatalf("wanted error to be nil, got %v instead", err)
+			}
+			if !*global {
+				t.Errorf("wanted -%s global flag to be set", tc.name)
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package clino
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+)
+
+// Formats supported by Printer.
+const (
+	// FormatTable prints values as a table with a header, aligned with text/tabwriter.
+	FormatTable = "table"
+
+	// FormatJSON prints values as an indented JSON document.
+	FormatJSON = "json"
+
+	// FormatJSONL prints values as JSON lines: each value of a slice is printed as a JSON object on its own line.
+	FormatJSONL = "jsonl"
+
+	// FormatCSV prints values as comma-separated values with a header.
+	FormatCSV = "csv"
+
+	// FormatTemplate prints each value using the Go template that follows it, such as go-template={{.Name}}.
+	FormatTemplate = "go-template="
+)
+
+// Printer renders values in a given format.
+//
+// It is mostly useful for commands listing or getting resources.
+// Values are usually a slice of structs, but a single struct or other values are accepted too.
+// Columns are the exported fields of the struct, named after their json tag, if any.
+// Fields with the json:"-" tag are skipped.
+//
+// When Program.FormatFlag is set, you can use the Print function to print with the format chosen by the user.
+type Printer struct {
+	// Format of the output: table, json, jsonl, csv, or go-template=TEMPLATE.
+	// If empty, FormatTable is used.
+	Format string
+
+	// Output to write to.
+	Output io.Writer
+}
+
+// Print the value in the format of the printer.
+func (pr *Printer) Print(v interface{}) error {
+	w := pr.Output
+	if w == nil {
+		w = os.Stdout
+	}
+	switch format := pr.Format; {
+	case format == "" || format == FormatTable:
+		return printTable(w, v)
+	case format == FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case format == FormatJSONL:
+		enc := json.NewEncoder(w)
+		for _, row := range rows(v) {
+			if err := enc.Encode(row.Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	case format == FormatCSV:
+		return printCSV(w, v)
+	case strings.HasPrefix(format, FormatTemplate):
+		return printTemplate(w, strings.TrimPrefix(format, FormatTemplate), v)
+	default:
+		return fmt.Errorf("unknown format: %q", format)
+	}
+}
+
+// Print the value to the output of the program with the format chosen by the user on the -format flag.
+//
+// If Program.FormatFlag isn't set, it prints a table.
+// If the context doesn't come from a command run by Program, it prints a table to os.Stdout.
+func Print(ctx context.Context, v interface{}) error {
+	pr, ok := ctx.Value(printerKey).(*Printer)
+	if !ok {
+		pr = &Printer{}
+	}
+	return pr.Print(v)
+}
+
+// formatValue is the value of the -format flag.
+type formatValue string
+
+func (f *formatValue) String() string { return string(*f) }
+
+func (f *formatValue) Set(v string) error {
+	switch {
+	case v == FormatTable, v == FormatJSON, v == FormatJSONL, v == FormatCSV:
+	case strings.HasPrefix(v, FormatTemplate):
+		if _, err := template.New("format").Parse(strings.TrimPrefix(v, FormatTemplate)); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("must be %s, %s, %s, %s, or %sTEMPLATE", FormatTable, FormatJSON, FormatJSONL, FormatCSV, FormatTemplate)
+	}
+	*f = formatValue(v)
+	return nil
+}
+
+// rows returns the elements of a slice or array, or the value itself otherwise.
+func rows(v interface{}) []reflect.Value {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return nil
+	}
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return []reflect.Value{rv}
+	}
+	var out []reflect.Value
+	for i := 0; i < rv.Len(); i++ {
+		out = append(out, rv.Index(i))
+	}
+	return out
+}
+
+// column of a table or CSV output.
+type column struct {
+	name  string
+	index int
+}
+
+// columns of the values, which are the exported fields of structs, or a single VALUE column otherwise.
+func columns(v interface{}) []column {
+	typ := reflect.TypeOf(v)
+	if typ != nil && (typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array) {
+		typ = typ.Elem()
+	}
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct || typ.Implements(stringerType) || reflect.PtrTo(typ).Implements(stringerType) {
+		return []column{{name: "VALUE", index: -1}}
+	}
+	var cols []column
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" { // unexported
+			continue
+		}
+		name := f.Name
+		if tag := strings.Split(f.Tag.Get("json"), ",")[0]; tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+		cols = append(cols, column{name: name, index: i})
+	}
+	return cols
+}
+
+var stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+// cells of a row.
+func cells(row reflect.Value, cols []column) []string {
+	var out []string
+	for _, c := range cols {
+		v := row
+		if c.index != -1 {
+			for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+				if v.IsNil() {
+					break
+				}
+				v = v.Elem()
+			}
+			if v.Kind() != reflect.Struct {
+				out = append(out, "")
+				continue
+			}
+			v = v.Field(c.index)
+		}
+		out = append(out, cell(v))
+	}
+	return out
+}
+
+func cell(v reflect.Value) string {
+	if !v.IsValid() || ((v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil()) {
+		return ""
+	}
+	if v.Kind() == reflect.Ptr {
+		if _, ok := v.Interface().(fmt.Stringer); !ok {
+			v = v.Elem()
+		}
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func printTable(w io.Writer, v interface{}) error {
+	cols := columns(v)
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	var header []string
+	for _, c := range cols {
+		header = append(header, strings.ToUpper(c.name))
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows(v) {
+		fmt.Fprintln(tw, strings.Join(cells(row, cols), "\t"))
+	}
+	return tw.Flush()
+}
+
+func printCSV(w io.Writer, v interface{}) error {
+	cols := columns(v)
+	cw := csv.NewWriter(w)
+	var header []string
+	for _, c := range cols {
+		header = append(header, c.name)
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, row := range rows(v) {
+		if err := cw.Write(cells(row, cols)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func printTemplate(w io.Writer, text string, v interface{}) error {
+	tmpl, err := template.New("format").Parse(text)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows(v) {
+		if err := tmpl.Execute(w, row.Interface()); err != nil {
+			return err
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package clino
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func testPlanets() []planet {
+	ring := true
+	return []planet{
+		{Name: "Earth", Moons: 1, Distance: 1, note: "home"},
+		{Name: "Saturn", Moons: 82, Ring: &ring, Distance: 9.5},
+	}
+}
+
+func TestPrinter(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		format string
+		in     interface{}
+		want   string
+		err    string
+	}{
+		{
+			desc: "default table",
+			in:   testPlanets(),
+			want: `NAME     MOONS   RING
+Earth    1       
+Saturn   82      true
+`,
+		},
+		{
+			desc:   "table of pointers",
+			format: FormatTable,
+			in:     []*planet{{Name: "Mars", Moons: 2}, nil},
+			want: `NAME   MOONS   RING
+Mars   2       
+               
+`,
+		},
+		{
+			desc:   "table of a struct",
+			format: FormatTable,
+			in:     planet{Name: "Mercury"},
+			want: `NAME      MOONS   RING
+Mercury   0       
+`,
+		},
+		{
+			desc:   "table of strings",
+			format: FormatTable,
+			in:     []string{"Venus", "Jupiter"},
+			want: `VALUE
+Venus
+Jupiter
+`,
+		},
+		{
+			desc:   "json",
+			format: FormatJSON,
+			in:     testPlanets()[:1],
+			want: `[
+  {
+    "name": "Earth",
+    "moons": 1
+  }
+]
+`,
+		},
+		{
+			desc:   "jsonl",
+			format: FormatJSONL,
+			in:     testPlanets(),
+			want: `{"name":"Earth","moons":1}
+{"name":"Saturn","moons":82,"ring":true}
+`,
+		},
+		{
+			desc:   "csv",
+			format: FormatCSV,
+			in: append(testPlanets(), planet{
+				Name: `The "Red", Planet`,
+			}),
+			want: `name,moons,ring
+Earth,1,
+Saturn,82,true
+"The ""Red"", Planet",0,
+`,
+		},
+		{
+			desc:   "go-template",
+			format: "go-template={{.Name}} has {{.Moons}} moon(s)",
+			in:     testPlanets(),
+			want: `Earth has 1 moon(s)
+Saturn has 82 moon(s)
+`,
+		},
+		{
+			desc:   "go-template with error",
+			format: "go-template={{.Name",
+			in:     testPlanets(),
+			err:    `template: format:1: unclosed action`,
+		},
+		{
+			desc:   "unknown format",
+			format: "yaml",
+			in:     testPlanets(),
+			err:    `unknown format: "yaml"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var buf bytes.Buffer
+			pr := &Printer{Format: tc.format, Output: &buf}
+			err := pr.Print(tc.in)
+			if (err == nil && tc.err != "") || (err != nil && err.Error() != tc.err) {
+				t.Errorf("wanted error to be %v, got %v instead", tc.err, err)
+			}
+			if got := buf.String(); got != tc.want {
+				t.Errorf("got output %q, wanted %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestProgramFormatFlag(t *testing.T) {
+	testCases := []struct {
+		desc string
+		args []string
+		want string
+		err  string
+	}{
+		{
+			desc: "default format",
+			want: "NAME     MOONS   RING\nEarth    1       \nSaturn   82      true\n",
+		},
+		{
+			desc: "jsonl",
+			args: []string{"-format", "jsonl"},
+			want: "{\"name\":\"Earth\",\"moons\":1}\n{\"name\":\"Saturn\",\"moons\":82,\"ring\":true}\n",
+		},
+		{
+			desc: "invalid format",
+			args: []string{"-format=yaml"},
+			err:  `invalid value "yaml" for flag -format: must be table, json, jsonl, csv, or go-template=TEMPLATE`,
+		},
+		{
+			desc: "invalid template",
+			args: []string{"-format={{.Name"},
+			err:  `invalid value "{{.Name" for flag -format: must be table, json, jsonl, csv, or go-template=TEMPLATE`,
+		},
+		{
+			desc: "invalid go-template",
+			args: []string{"-format=go-template={{.Name"},
+			err:  `invalid value "go-template={{.Name" for flag -format: template: format:1: unclosed action`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var buf bytes.Buffer
+			p := Program{
+				Root:       &listCommand{planets: testPlanets()},
+				Output:     &buf,
+				FormatFlag: true,
+			}
+			err := p.Run(context.Background(), tc.args...)
+			if (err == nil && tc.err != "") || (err != nil && err.Error() != tc.err) {
+				t.Errorf("wanted error to be %v, got %v instead", tc.err, err)
+			}
+			if got := buf.String(); got != tc.want {
+				t.Errorf("got output %q, wanted %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestProgramFormatFlagHelp(t *testing.T) {
+	var buf bytes.Buffer
+	p := Program{
+		Root:       &listCommand{},
+		Output:     &buf,
+		FormatFlag: true,
+	}
+	if err := p.Run(context.Background(), "-h"); err != nil {
+		t.Errorf("wanted error to be nil, got %v instead", err)
+	}
+	if want := "-format (value)        output format: table, json, jsonl, csv, or go-template=TEMPLATE (default table)"; !strings.Contains(buf.String(), want) {
+		t.Errorf("wanted help output to contain %q, got %v instead", want, buf.String())
+	}
+}
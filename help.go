@@ -18,6 +18,7 @@ type helper struct {
 	Foot func() string
 
 	Commands []Command
+	Plugins  []string
 
 	binary   string
 	trail    []string
@@ -64,6 +65,7 @@ func (h *helper) Run(ctx context.Context) (err error) {
 	}
 	w := tabwriter.NewWriter(h.Output, 0, 0, 8, ' ', 0)
 	h.helpCommands(w)
+	h.helpPlugins(w)
 	if h.usable {
 		h.helpFlags(w)
 	}
@@ -99,6 +101,17 @@ func (h *helper) helpCommands(w io.Writer) {
 	fmt.Fprintln(w, "\t\t")
 }
 
+func (h *helper) helpPlugins(w io.Writer) {
+	if len(h.Plugins) == 0 {
+		return
+	}
+	fmt.Fprint(w, "\tPlugins:\t\n\t")
+	for _, name := range h.Plugins {
+		fmt.Fprintf(w, "%s\t\n\t", name)
+	}
+	fmt.Fprintln(w, "\t\t")
+}
+
 func (h *helper) helpFlags(w io.Writer) {
 	fmt.Fprintln(w, "\tFlags:\t") // \t\t keeps the alignment between commands and flags on tabwriter
 	if h.fs != nil {
@@ -110,7 +123,15 @@ func (h *helper) helpFlags(w io.Writer) {
 }
 
 func printFlag(w io.Writer, f *flag.Flag) {
+	fv, file := f.Value.(*fileValue)
+	if file {
+		// describe the wrapped flag value instead.
+		f = &flag.Flag{Name: f.Name, Usage: f.Usage, Value: fv.Value, DefValue: f.DefValue}
+	}
 	typ, usage := flag.UnquoteUsage(f)
+	if file {
+		usage += fileValueUsage
+	}
 	if typ == "" { // type: bool flag
 		fmt.Fprintf(w, "\t-%s\t%s", f.Name, usage)
 	} else {
@@ -0,0 +1,90 @@
+package clino
+
+import (
+	"context"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestLazyCommands(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		lazy    bool
+		version bool
+		args    []string
+		want    map[string]int
+	}{
+		{
+			desc: "eager",
+			args: []string{"a"},
+			want: map[string]int{
+				"Commands app": 2, // checking for duplicated commands, and finding the command.
+				"Commands a":   1,
+				"Commands b":   1,
+			},
+		},
+		{
+			desc: "eager help",
+			args: []string{"help", "a"},
+			want: map[string]int{
+				"Commands app": 2,
+				"Commands a":   2,
+				"Commands b":   1,
+			},
+		},
+		{
+			desc: "lazy",
+			lazy: true,
+			args: []string{"a"},
+			want: map[string]int{
+				"Lookup app a": 1,
+			},
+		},
+		{
+			desc: "lazy help",
+			lazy: true,
+			args: []string{"help", "a"},
+			want: map[string]int{
+				"Lookup app a": 1,
+				"Commands a":   1,
+			},
+		},
+		{
+			desc: "lazy root help",
+			lazy: true,
+			args: []string{"help"},
+			want: map[string]int{
+				"Commands app": 1,
+			},
+		},
+		{
+			desc:    "lazy version command",
+			lazy:    true,
+			version: true,
+			args:    []string{"version"},
+			want: map[string]int{
+				"Lookup app version": 1,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			calls := map[string]int{}
+			p := Program{
+				Root:         &lazyCommand{name: "app", children: []string{"a", "b"}, calls: calls},
+				Output:       ioutil.Discard,
+				LazyCommands: tc.lazy,
+			}
+			if tc.version {
+				p.Version = &Version{}
+			}
+			if err := p.Run(context.Background(), tc.args...); err != nil {
+				t.Errorf("wanted error to be nil, got %v instead", err)
+			}
+			if !reflect.DeepEqual(calls, tc.want) {
+				t.Errorf("got calls %v, wanted %v", calls, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package clino
+
+import "context"
+
+// RunFunc is the signature of the Run function of a Runnable command.
+type RunFunc func(ctx context.Context, args ...string) error
+
+// Middleware wraps the execution of a command.
+//
+// It is useful for adding timing, tracing, audit logging, or context values to all commands at once.
+// You can use the Trail function to get the command being run from the context.
+// 	func Timing(next clino.RunFunc) clino.RunFunc {
+// 		return func(ctx context.Context, args ...string) error {
+// 			start := time.Now()
+// 			defer func() {
+// 				trail := clino.Trail(ctx)
+// 				log.Printf("%s took %v", trail[len(trail)-1].Name(), time.Since(start))
+// 			}()
+// 			return next(ctx, args...)
+// 		}
+// 	}
+type Middleware func(next RunFunc) RunFunc
+
+// chain the middleware around the run function.
+// The first middleware is the outermost one.
+func chain(run RunFunc, middleware []Middleware) RunFunc {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		run = middleware[i](run)
+	}
+	return run
+}
+
+// Trail of the command being run, from the root command to the command itself.
+// It returns nil if the context doesn't come from a command run by Program.
+func Trail(ctx context.Context) []Command {
+	trail, _ := ctx.Value(trailKey).([]Command)
+	return trail
+}
@@ -0,0 +1,80 @@
+package clino
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProgramMiddleware(t *testing.T) {
+	log := &hooksLog{}
+	mw := func(name string) Middleware {
+		return func(next RunFunc) RunFunc {
+			return func(ctx context.Context, args ...string) error {
+				var names []string
+				for _, c := range Trail(ctx) {
+					names = append(names, c.Name())
+				}
+				log.call(fmt.Sprintf("%s before %s", name, strings.Join(names, " ")))
+				err := next(ctx, append(args, name)...)
+				log.call(fmt.Sprintf("%s after (err=%v)", name, err))
+				return err
+			}
+		}
+	}
+	p := Program{
+		Root:       &hooksRootCommand{log: log},
+		Output:     ioutil.Discard,
+		Middleware: []Middleware{mw("outer"), mw("inner")},
+	}
+	if err := p.Run(context.Background(), "parent", "child", "x"); err != nil {
+		t.Errorf("wanted error to be nil, got %v instead", err)
+	}
+	want := []string{
+		"outer before hooks parent child",
+		"inner before hooks parent child",
+		"root persistent pre-run (verbose=false)",
+		"parent persistent pre-run",
+		"child pre-run [x outer inner]",
+		"child run [x outer inner]",
+		"child post-run [x outer inner]",
+		"root persistent post-run",
+		"inner after (err=<nil>)",
+		"outer after (err=<nil>)",
+	}
+	if !reflect.DeepEqual(log.calls, want) {
+		t.Errorf("wanted calls %q, got %q instead", want, log.calls)
+	}
+}
+
+func TestProgramMiddlewareShortCircuit(t *testing.T) {
+	log := &hooksLog{}
+	errDenied := errors.New("permission denied")
+	p := Program{
+		Root:   &hooksRootCommand{log: log},
+		Output: ioutil.Discard,
+		Middleware: []Middleware{
+			func(next RunFunc) RunFunc {
+				return func(ctx context.Context, args ...string) error {
+					return errDenied
+				}
+			},
+		},
+	}
+	if err := p.Run(context.Background(), "parent", "child"); err != errDenied {
+		t.Errorf("wanted error to be %v, got %v instead", errDenied, err)
+	}
+	if len(log.calls) != 0 {
+		t.Errorf("expected command not to run, got calls %q", log.calls)
+	}
+}
+
+func TestTrailOutsideProgram(t *testing.T) {
+	if trail := Trail(context.Background()); trail != nil {
+		t.Errorf("expected no trail, got %v instead", trail)
+	}
+}
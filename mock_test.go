@@ -1,9 +1,12 @@
 package clino
 
 import (
+	"bytes"
 	"context"
 	"flag"
 	"fmt"
+	"io"
+	"strings"
 	"time"
 )
 
@@ -216,3 +219,396 @@ func (s *innerCommand) Commands() []Command {
 		&simpleCommand{},
 	}
 }
+
+// hooksLog records the calls to the run functions of the hooks commands.
+type hooksLog struct {
+	calls []string
+	fail  map[string]error
+}
+
+func (hl *hooksLog) call(name string) error {
+	hl.calls = append(hl.calls, name)
+	return hl.fail[name]
+}
+
+// hooksRootCommand implements the persistent pre-run and post-run hooks.
+type hooksRootCommand struct {
+	log     *hooksLog
+	verbose bool
+}
+
+func (hrc *hooksRootCommand) Name() string {
+	return "hooks"
+}
+
+func (hrc *hooksRootCommand) Commands() []Command {
+	return []Command{
+		&hooksParentCommand{log: hrc.log},
+	}
+}
+
+func (hrc *hooksRootCommand) PersistentFlags(flags *flag.FlagSet) {
+	flags.BoolVar(&hrc.verbose, "verbose", false, "verbose mode")
+}
+
+func (hrc *hooksRootCommand) PersistentPreRun(ctx context.Context, args ...string) error {
+	return hrc.log.call(fmt.Sprintf("root persistent pre-run (verbose=%v)", hrc.verbose))
+}
+
+func (hrc *hooksRootCommand) PersistentPostRun(ctx context.Context, args ...string) error {
+	return hrc.log.call("root persistent post-run")
+}
+
+// hooksParentCommand implements only the persistent pre-run hook.
+type hooksParentCommand struct {
+	log *hooksLog
+}
+
+func (hpc *hooksParentCommand) Name() string {
+	return "parent"
+}
+
+func (hpc *hooksParentCommand) Commands() []Command {
+	return []Command{
+		&hooksCommand{log: hpc.log},
+	}
+}
+
+func (hpc *hooksParentCommand) PersistentPreRun(ctx context.Context, args ...string) error {
+	return hpc.log.call("parent persistent pre-run")
+}
+
+// hooksCommand implements the pre-run and post-run hooks.
+type hooksCommand struct {
+	log *hooksLog
+}
+
+func (hc *hooksCommand) Name() string {
+	return "child"
+}
+
+func (hc *hooksCommand) PreRun(ctx context.Context, args ...string) error {
+	return hc.log.call(fmt.Sprintf("child pre-run %v", args))
+}
+
+func (hc *hooksCommand) Run(ctx context.Context, args ...string) error {
+	return hc.log.call(fmt.Sprintf("child run %v", args))
+}
+
+func (hc *hooksCommand) PostRun(ctx context.Context, args ...string) error {
+	return hc.log.call(fmt.Sprintf("child post-run %v", args))
+}
+
+// waitCommand blocks until its context is done, and then until release is closed, if set.
+type waitCommand struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (wc *waitCommand) Name() string {
+	return "wait"
+}
+
+func (wc *waitCommand) Run(ctx context.Context, args ...string) error {
+	close(wc.started)
+	<-ctx.Done()
+	if wc.release != nil {
+		<-wc.release
+	}
+	return ctx.Err()
+}
+
+// timeoutCommand waits until its context is done, and has a default timeout.
+type timeoutCommand struct {
+	timeout time.Duration
+}
+
+func (tc *timeoutCommand) Name() string {
+	return "timeout"
+}
+
+func (tc *timeoutCommand) Timeout() time.Duration {
+	return tc.timeout
+}
+
+func (tc *timeoutCommand) Run(ctx context.Context, args ...string) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// panicCommand panics when it runs.
+type panicCommand struct {
+	token string
+}
+
+func (pc *panicCommand) Name() string {
+	return "panic"
+}
+
+func (pc *panicCommand) Flags(flags *flag.FlagSet) {
+	flags.StringVar(&pc.token, "token", "", "API token")
+}
+
+func (pc *panicCommand) Run(ctx context.Context, args ...string) error {
+	panic("something went wrong")
+}
+
+// quotaError is a domain error with its own exit code.
+type quotaError struct{}
+
+func (qe quotaError) Error() string { return "quota exceeded" }
+
+func (qe quotaError) ExitCode() int { return ExitTempFail }
+
+// failCommand fails with the given error.
+type failCommand struct {
+	err error
+}
+
+func (fc *failCommand) Name() string {
+	return "fail"
+}
+
+func (fc *failCommand) Run(ctx context.Context, args ...string) error {
+	return fc.err
+}
+
+// namedCommand has only a name.
+type namedCommand struct {
+	name string
+}
+
+func (nc *namedCommand) Name() string {
+	return nc.name
+}
+
+// planet is used for testing the output formats.
+type planet struct {
+	Name     string  `json:"name"`
+	Moons    int     `json:"moons"`
+	Ring     *bool   `json:"ring,omitempty"`
+	Distance float64 `json:"-"`
+	note     string
+}
+
+// listCommand prints a list of planets.
+type listCommand struct {
+	planets []planet
+}
+
+func (lc *listCommand) Name() string {
+	return "list"
+}
+
+func (lc *listCommand) Run(ctx context.Context, args ...string) error {
+	return Print(ctx, lc.planets)
+}
+
+// echoCommand copies its input to its output, and writes its arguments to its error output.
+type echoCommand struct {
+	terminals []bool
+}
+
+func (ec *echoCommand) Name() string {
+	return "echo"
+}
+
+func (ec *echoCommand) Run(ctx context.Context, args ...string) error {
+	streams := IO(ctx)
+	ec.terminals = []bool{streams.IsInputTerminal(), streams.IsOutputTerminal(), streams.IsErrorTerminal()}
+	fmt.Fprintln(streams.Err, strings.Join(args, " "))
+	_, err := io.Copy(streams.Out, streams.In)
+	return err
+}
+
+// fakeTerminal is a buffer simulating a terminal.
+type fakeTerminal struct {
+	bytes.Buffer
+}
+
+func (ft *fakeTerminal) IsTerminal() bool {
+	return true
+}
+
+// fakeTerminalInput is an input simulating a terminal.
+type fakeTerminalInput struct {
+	*strings.Reader
+}
+
+func (fti fakeTerminalInput) IsTerminal() bool {
+	return true
+}
+
+// confirmCommand asks for confirmation before running.
+type confirmCommand struct {
+	confirmed bool
+}
+
+func (cc *confirmCommand) Name() string {
+	return "confirm"
+}
+
+func (cc *confirmCommand) Run(ctx context.Context, args ...string) (err error) {
+	cc.confirmed, err = Confirm(ctx, "Delete all files?", false)
+	return err
+}
+
+// treeCommand is a configurable command for validating command trees.
+type treeCommand struct {
+	name       string
+	short      string
+	flags      []string
+	persistent []string
+	children   []Command
+	values     map[string]*bool
+	args       []string
+}
+
+func (tc *treeCommand) Name() string {
+	return tc.name
+}
+
+func (tc *treeCommand) Short() string {
+	return tc.short
+}
+
+func (tc *treeCommand) Commands() []Command {
+	return tc.children
+}
+
+func (tc *treeCommand) Flags(flags *flag.FlagSet) {
+	tc.define(flags, tc.flags)
+}
+
+func (tc *treeCommand) PersistentFlags(flags *flag.FlagSet) {
+	tc.define(flags, tc.persistent)
+}
+
+func (tc *treeCommand) define(flags *flag.FlagSet, names []string) {
+	if tc.values == nil {
+		tc.values = map[string]*bool{}
+	}
+	for _, name := range names {
+		tc.values[name] = flags.Bool(name, false, "")
+	}
+}
+
+func (tc *treeCommand) Run(ctx context.Context, args ...string) error {
+	tc.args = args
+	return nil
+}
+
+// bareCommand only implements the Command interface.
+type bareCommand struct {
+	name string
+}
+
+func (bc *bareCommand) Name() string {
+	return bc.name
+}
+
+// lazyCommand records calls creating its subcommands.
+type lazyCommand struct {
+	name     string
+	children []string
+	calls    map[string]int
+}
+
+func (lc *lazyCommand) Name() string {
+	return lc.name
+}
+
+func (lc *lazyCommand) Short() string {
+	return "lazy command"
+}
+
+func (lc *lazyCommand) Commands() []Command {
+	lc.calls["Commands "+lc.name]++
+	var commands []Command
+	for _, name := range lc.children {
+		commands = append(commands, &lazyCommand{name: name, calls: lc.calls})
+	}
+	return commands
+}
+
+func (lc *lazyCommand) Lookup(name string) Command {
+	lc.calls["Lookup "+lc.name+" "+name]++
+	for _, c := range lc.children {
+		if c == name {
+			return &lazyCommand{name: name, calls: lc.calls}
+		}
+	}
+	return nil
+}
+
+func (lc *lazyCommand) Run(ctx context.Context, args ...string) error {
+	return nil
+}
+
+// funcCommand runs a function.
+type funcCommand struct {
+	run func(ctx context.Context, args ...string) error
+}
+
+func (fc *funcCommand) Name() string {
+	return "func"
+}
+
+func (fc *funcCommand) Run(ctx context.Context, args ...string) error {
+	return fc.run(ctx, args...)
+}
+
+// defaultParentCommand runs its default subcommand.
+type defaultParentCommand struct {
+	name     string
+	def      string
+	children []Command
+}
+
+func (dpc *defaultParentCommand) Name() string {
+	return dpc.name
+}
+
+func (dpc *defaultParentCommand) Short() string {
+	return "parent with a default command"
+}
+
+func (dpc *defaultParentCommand) Commands() []Command {
+	return dpc.children
+}
+
+func (dpc *defaultParentCommand) DefaultCommand() string {
+	return dpc.def
+}
+
+// fileFlagsCommand has flags that can be read from files.
+type fileFlagsCommand struct {
+	token string
+	body  string
+	count int
+}
+
+func (ffc *fileFlagsCommand) Name() string {
+	return "send"
+}
+
+func (ffc *fileFlagsCommand) Flags(flags *flag.FlagSet) {
+	StringFileVar(flags, &ffc.token, "token", "", "API `token`")
+	StringFileVar(flags, &ffc.body, "body", "{}", "request body")
+	var fs flag.FlagSet
+	fs.IntVar(&ffc.count, "count", 1, "number of requests")
+	flags.Var(FileValue(fs.Lookup("count").Value), "count", "number of requests")
+}
+
+func (ffc *fileFlagsCommand) Run(ctx context.Context, args ...string) error {
+	return nil
+}
+
+// argsCommand accepts positional arguments when run as a default command.
+type argsCommand struct {
+	*treeCommand
+}
+
+func (ac argsCommand) AcceptsArgs() bool {
+	return true
+}
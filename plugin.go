@@ -0,0 +1,90 @@
+package clino
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// pluginPrefix returns the prefix of the names of the plugin executables, such as "app-" for the "app" root command.
+func (p *Program) pluginPrefix() string {
+	return p.Root.Name() + "-"
+}
+
+// lookPlugin returns the path of the plugin invoked by the arguments, if any.
+// Commands of the root command take precedence over plugins.
+func (p *Program) lookPlugin(args []string) (path string, ok bool) {
+	if len(args) == 0 || args[0] == "" || args[0] == "help" || strings.HasPrefix(args[0], "-") || strings.ContainsAny(args[0], `/\`) {
+		return "", false
+	}
+	if _, ok := p.lookupCommand([]Command{p.Root}, args[0]); ok {
+		return "", false
+	}
+	path, err := exec.LookPath(p.pluginPrefix() + args[0])
+	return path, err == nil
+}
+
+// runPlugin executes the plugin with the arguments, using the input and output streams of the program.
+// If the plugin fails, the returned *exec.ExitError carries its exit code.
+func (p *Program) runPlugin(ctx context.Context, path string, args []string) error {
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Stdin = p.Input
+	cmd.Stdout = p.Output
+	cmd.Stderr = p.ErrOutput
+	return cmd.Run()
+}
+
+// plugins returns the sorted names of the plugins found on PATH.
+// Plugins with the same name as a command of the root command, or found later on PATH, are skipped.
+func (p *Program) plugins() []string {
+	seen := map[string]bool{}
+	for _, c := range p.rootCommands() {
+		seen[c.Name()] = true
+	}
+	var names []string
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+		d, err := os.Open(dir)
+		if err != nil {
+			continue
+		}
+		files, _ := d.Readdirnames(-1)
+		d.Close()
+		for _, file := range files {
+			name, ok := pluginName(dir, file, p.pluginPrefix())
+			if ok && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// pluginName returns the name of the plugin if the file in the directory is a plugin executable.
+// On Windows, plugins must have the .exe extension, which is removed from the name.
+func pluginName(dir, file, prefix string) (name string, ok bool) {
+	name = file
+	if runtime.GOOS == "windows" {
+		ext := filepath.Ext(name)
+		if !strings.EqualFold(ext, ".exe") {
+			return "", false
+		}
+		name = strings.TrimSuffix(name, ext)
+	}
+	if !strings.HasPrefix(name, prefix) || len(name) == len(prefix) {
+		return "", false
+	}
+	fi, err := os.Stat(filepath.Join(dir, file))
+	if err != nil || fi.IsDir() || (runtime.GOOS != "windows" && fi.Mode()&0111 == 0) {
+		return "", false
+	}
+	return strings.TrimPrefix(name, prefix), true
+}
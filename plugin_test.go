@@ -0,0 +1,130 @@
+package clino
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// setupPlugins creates plugin executables on a temporary directory, and sets PATH to it.
+// It returns a function to restore PATH and remove the directory.
+func setupPlugins(t *testing.T, plugins map[string]string) (restore func()) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+	dir, err := ioutil.TempDir("", "clino-plugins")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := os.Getenv("PATH")
+	restore = func() {
+		os.Setenv("PATH", path)
+		os.RemoveAll(dir)
+	}
+	for name, script := range plugins {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), 0755); err != nil {
+			restore()
+			t.Fatal(err)
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "app-not-executable"), nil, 0644); err != nil {
+		restore()
+		t.Fatal(err)
+	}
+	os.Setenv("PATH", dir)
+	return restore
+}
+
+func TestPlugins(t *testing.T) {
+	defer setupPlugins(t, map[string]string{
+		"app-hello":        `read line; echo "$line"; echo "hello $@"; echo oops >&2; exit 3`,
+		"app-not-runnable": `echo shadowed`,
+		"app-version":      `echo shadowed`,
+	})()
+	testCases := []struct {
+		desc       string
+		args       []string
+		wantOut    string
+		wantErrOut string
+		wantCode   int
+	}{
+		{
+			desc:       "plugin",
+			args:       []string{"hello", "-name", "world"},
+			wantOut:    "input\nhello -name world\n",
+			wantErrOut: "oops\n",
+			wantCode:   3,
+		},
+		{
+			desc:     "commands shadow plugins",
+			args:     []string{"not-runnable"},
+			wantOut:  "This is a not so long,\nmultiline help topic.\n",
+			wantCode: 0,
+		},
+		{
+			desc:     "built-in commands shadow plugins",
+			args:     []string{"version"},
+			wantOut:  "Version:     1.0.0\nGo version:  go1.16\n",
+			wantCode: 0,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			p := Program{
+				Root:      &rootCommand{},
+				Input:     strings.NewReader("input\n"),
+				Output:    &out,
+				ErrOutput: &errOut,
+				Version:   &Version{Version: "1.0.0", GoVersion: "go1.16"},
+				Plugins:   true,
+			}
+			err := p.Run(context.Background(), tc.args...)
+			if code := ExitCode(err); code != tc.wantCode {
+				t.Errorf("wanted exit code %d, got %d instead (error: %v)", tc.wantCode, code, err)
+			}
+			if got := out.String(); got != tc.wantOut {
+				t.Errorf("got output %q, wanted %q", got, tc.wantOut)
+			}
+			if got := errOut.String(); got != tc.wantErrOut {
+				t.Errorf("got error output %q, wanted %q", got, tc.wantErrOut)
+			}
+		})
+	}
+}
+
+func TestPluginsHelp(t *testing.T) {
+	defer setupPlugins(t, map[string]string{
+		"app-hello":        "",
+		"app-deploy":       "",
+		"app-not-runnable": "",
+		"other-tool":       "",
+	})()
+	var buf bytes.Buffer
+	p := Program{
+		Root:    &rootCommand{},
+		Output:  &buf,
+		Plugins: true,
+	}
+	if err := p.Run(context.Background(), "help"); err != nil {
+		t.Errorf("wanted error to be nil, got %v instead", err)
+	}
+	const golden = "testdata/root_help_plugins.golden"
+	if *update {
+		if err := ioutil.WriteFile(golden, buf.Bytes(), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bs, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("opening %s: %v", golden, err)
+	}
+	if got := buf.String(); got != string(bs) {
+		t.Errorf("got output %v\n, wanted %v", got, string(bs))
+	}
+}
@@ -0,0 +1,293 @@
+package clino
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// progressInterval and progressLogInterval are replaced during tests.
+var (
+	// progressInterval between redraws on a terminal.
+	progressInterval = 100 * time.Millisecond
+
+	// progressLogInterval between log lines when the error output isn't a terminal.
+	progressLogInterval = 5 * time.Second
+)
+
+var spinnerFrames = []string{"|", "/", "-", "\\"}
+
+// progress renders lines to the error output of the program until it is stopped or the context is done.
+//
+// On a terminal, the lines are redrawn periodically.
+// Otherwise, lines that changed are logged periodically, so logs aren't flooded.
+type progress struct {
+	mu       sync.Mutex
+	w        io.Writer
+	terminal bool
+	render   func(frame int) []string // called with mu held; frame is -1 when not animating.
+	frame    int
+	drawn    int
+	logged   []string
+
+	stop    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+}
+
+func startProgress(ctx context.Context, render func(frame int) []string) *progress {
+	streams := IO(ctx)
+	p := &progress{
+		w:        streams.Err,
+		terminal: streams.IsErrorTerminal(),
+		render:   render,
+		stop:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	interval := progressLogInterval
+	if p.terminal {
+		interval = progressInterval
+	}
+	p.draw()
+	go func() {
+		defer close(p.stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.draw()
+			case <-ctx.Done():
+				p.draw()
+				return
+			case <-p.stop:
+				p.draw()
+				return
+			}
+		}
+	}()
+	return p
+}
+
+// draw the current state of the progress.
+func (p *progress) draw() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.terminal {
+		lines := p.render(-1)
+		for i, line := range lines {
+			if i >= len(p.logged) || p.logged[i] != line {
+				fmt.Fprintln(p.w, line)
+			}
+		}
+		p.logged = lines
+		return
+	}
+	lines := p.render(p.frame)
+	p.frame++
+	if p.drawn != 0 {
+		fmt.Fprintf(p.w, "\x1b[%dA", p.drawn) // move the cursor up to redraw the lines
+	}
+	for _, line := range lines {
+		fmt.Fprintf(p.w, "\r\x1b[K%s\n", line)
+	}
+	p.drawn = len(lines)
+}
+
+// update the state of the progress while holding the lock.
+func (p *progress) update(f func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	f()
+}
+
+// finish stops rendering the progress after drawing it one last time.
+func (p *progress) finish() {
+	p.once.Do(func() {
+		close(p.stop)
+	})
+	<-p.stopped
+}
+
+// ProgressBar shows the progress of a task with a known size.
+//
+// It writes to the error output of the program only when it is a terminal,
+// and logs its progress periodically otherwise.
+// It stops automatically when the context is done, but you should always call Done when the task ends.
+// 	bar := clino.NewProgressBar(ctx, "Downloading", size)
+// 	defer bar.Done()
+// 	for ... {
+// 		bar.Add(n)
+// 	}
+type ProgressBar struct {
+	p       *progress
+	label   string
+	current int64
+	total   int64
+}
+
+// progressBarWidth is the number of characters inside the brackets of a progress bar.
+const progressBarWidth = 30
+
+// NewProgressBar starts showing a progress bar for a task with the given total size.
+func NewProgressBar(ctx context.Context, label string, total int64) *ProgressBar {
+	pb := &ProgressBar{
+		label: label,
+		total: total,
+	}
+	pb.p = startProgress(ctx, pb.render)
+	return pb
+}
+
+// Add n to the current progress.
+func (pb *ProgressBar) Add(n int64) {
+	pb.p.update(func() {
+		pb.current += n
+	})
+}
+
+// Set the current progress.
+func (pb *ProgressBar) Set(current int64) {
+	pb.p.update(func() {
+		pb.current = current
+	})
+}
+
+// Done stops showing the progress bar.
+func (pb *ProgressBar) Done() {
+	pb.p.finish()
+}
+
+func (pb *ProgressBar) render(frame int) []string {
+	current := pb.current
+	if current > pb.total {
+		current = pb.total
+	}
+	if current < 0 {
+		current = 0
+	}
+	var percent int64 = 100
+	if pb.total > 0 {
+		percent = 100 * current / pb.total
+	}
+	if frame == -1 {
+		return []string{fmt.Sprintf("%s: %d%% (%d/%d)", pb.label, percent, pb.current, pb.total)}
+	}
+	filled := int(percent) * progressBarWidth / 100
+	bar := strings.Repeat("=", filled)
+	if filled < progressBarWidth {
+		bar += ">" + strings.Repeat(" ", progressBarWidth-filled-1)
+	}
+	return []string{fmt.Sprintf("%s [%s] %3d%% (%d/%d)", pb.label, bar, percent, pb.current, pb.total)}
+}
+
+// Spinner shows that a task of unknown size is running.
+//
+// It writes to the error output of the program only when it is a terminal,
+// and logs when it starts and stops otherwise.
+// It stops automatically when the context is done, but you should always call Stop when the task ends.
+type Spinner struct {
+	p     *progress
+	label string
+	done  bool
+}
+
+// NewSpinner starts showing a spinner.
+func NewSpinner(ctx context.Context, label string) *Spinner {
+	s := &Spinner{label: label}
+	s.p = startProgress(ctx, s.render)
+	return s
+}
+
+// Stop showing the spinner.
+func (s *Spinner) Stop() {
+	s.p.update(func() {
+		s.done = true
+	})
+	s.p.finish()
+}
+
+func (s *Spinner) render(frame int) []string {
+	switch {
+	case s.done:
+		return []string{s.label + ": done"}
+	case frame == -1:
+		return []string{s.label + "..."}
+	}
+	return []string{s.label + " " + spinnerFrames[frame%len(spinnerFrames)]}
+}
+
+// TaskList shows the status of multiple tasks, one per line.
+//
+// It writes to the error output of the program only when it is a terminal,
+// and logs when tasks start and end otherwise.
+// It stops automatically when the context is done, but you should always call Stop when all tasks end.
+// 	tasks := clino.NewTaskList(ctx)
+// 	defer tasks.Stop()
+// 	build := tasks.Add("build")
+// 	build.Done(runBuild(ctx))
+type TaskList struct {
+	p     *progress
+	tasks []*Task
+}
+
+// Task in a TaskList.
+type Task struct {
+	tl   *TaskList
+	name string
+	done bool
+	err  error
+}
+
+// NewTaskList starts showing a list of tasks.
+func NewTaskList(ctx context.Context) *TaskList {
+	tl := &TaskList{}
+	tl.p = startProgress(ctx, tl.render)
+	return tl
+}
+
+// Add a running task to the list.
+func (tl *TaskList) Add(name string) *Task {
+	t := &Task{
+		tl:   tl,
+		name: name,
+	}
+	tl.p.update(func() {
+		tl.tasks = append(tl.tasks, t)
+	})
+	return t
+}
+
+// Stop showing the task list.
+func (tl *TaskList) Stop() {
+	tl.p.finish()
+}
+
+// Done marks the task as finished, successfully if err is nil or failed otherwise.
+func (t *Task) Done(err error) {
+	t.tl.p.update(func() {
+		t.done, t.err = true, err
+	})
+}
+
+func (tl *TaskList) render(frame int) []string {
+	var lines []string
+	for _, t := range tl.tasks {
+		var status string
+		switch {
+		case t.err != nil:
+			status = fmt.Sprintf("failed: %v", t.err)
+		case t.done:
+			status = "done"
+		case frame == -1:
+			status = "running"
+		default:
+			status = "running " + spinnerFrames[frame%len(spinnerFrames)]
+		}
+		lines = append(lines, t.name+": "+status)
+	}
+	return lines
+}
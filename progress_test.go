@@ -0,0 +1,164 @@
+package clino
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+// progressContext returns a context with the given error output for testing the progress reporting.
+func progressContext(ctx context.Context, stderr io.Writer) context.Context {
+	return context.WithValue(ctx, ioKey, IOStreams{Err: stderr})
+}
+
+// slowProgress stops periodic redraws during the test.
+func slowProgress() (restore func()) {
+	interval, logInterval := progressInterval, progressLogInterval
+	progressInterval, progressLogInterval = time.Hour, time.Hour
+	return func() {
+		progressInterval, progressLogInterval = interval, logInterval
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	defer slowProgress()()
+	var stderr bytes.Buffer
+	pb := NewProgressBar(progressContext(context.Background(), &stderr), "Downloading", 200)
+	pb.Add(50)
+	pb.p.draw()
+	pb.p.draw() // unchanged lines are not logged again.
+	pb.Set(200)
+	pb.Done()
+	pb.Done() // no-op
+	want := `Downloading: 0% (0/200)
+Downloading: 25% (50/200)
+Downloading: 100% (200/200)
+`
+	if got := stderr.String(); got != want {
+		t.Errorf("got output %q, wanted %q", got, want)
+	}
+}
+
+func TestProgressBarTerminal(t *testing.T) {
+	defer slowProgress()()
+	stderr := &fakeTerminal{}
+	pb := NewProgressBar(progressContext(context.Background(), stderr), "Downloading", 200)
+	pb.Add(50)
+	pb.Done()
+	want := "\r\x1b[KDownloading [>                             ]   0% (0/200)\n" +
+		"\x1b[1A\r\x1b[KDownloading [=======>                      ]  25% (50/200)\n"
+	if got := stderr.String(); got != want {
+		t.Errorf("got output %q, wanted %q", got, want)
+	}
+}
+
+func TestProgressBarTerminalNegative(t *testing.T) {
+	defer slowProgress()()
+	stderr := &fakeTerminal{}
+	pb := NewProgressBar(progressContext(context.Background(), stderr), "Downloading", 200)
+	pb.Set(-5)
+	pb.p.draw()
+	pb.Add(-10)
+	pb.Done()
+	want := "\r\x1b[KDownloading [>                             ]   0% (0/200)\n" +
+		"\x1b[1A\r\x1b[KDownloading [>                             ]   0% (-5/200)\n" +
+		"\x1b[1A\r\x1b[KDownloading [>                             ]   0% (-15/200)\n"
+	if got := stderr.String(); got != want {
+		t.Errorf("got output %q, wanted %q", got, want)
+	}
+}
+
+func TestSpinner(t *testing.T) {
+	defer slowProgress()()
+	var stderr bytes.Buffer
+	s := NewSpinner(progressContext(context.Background(), &stderr), "Waiting")
+	s.Stop()
+	if want := "Waiting...\nWaiting: done\n"; stderr.String() != want {
+		t.Errorf("got output %q, wanted %q", stderr.String(), want)
+	}
+}
+
+func TestSpinnerTerminal(t *testing.T) {
+	defer slowProgress()()
+	stderr := &fakeTerminal{}
+	s := NewSpinner(progressContext(context.Background(), stderr), "Waiting")
+	s.p.draw()
+	s.Stop()
+	want := "\r\x1b[KWaiting |\n" +
+		"\x1b[1A\r\x1b[KWaiting /\n" +
+		"\x1b[1A\r\x1b[KWaiting: done\n"
+	if got := stderr.String(); got != want {
+		t.Errorf("got output %q, wanted %q", got, want)
+	}
+}
+
+func TestTaskList(t *testing.T) {
+	defer slowProgress()()
+	var stderr bytes.Buffer
+	tl := NewTaskList(progressContext(context.Background(), &stderr))
+	build, test := tl.Add("build"), tl.Add("test")
+	tl.p.draw()
+	build.Done(nil)
+	test.Done(errors.New("2 tests failed"))
+	tl.Stop()
+	want := `build: running
+test: running
+build: done
+test: failed: 2 tests failed
+`
+	if got := stderr.String(); got != want {
+		t.Errorf("got output %q, wanted %q", got, want)
+	}
+}
+
+func TestTaskListTerminal(t *testing.T) {
+	defer slowProgress()()
+	stderr := &fakeTerminal{}
+	tl := NewTaskList(progressContext(context.Background(), stderr))
+	build := tl.Add("build")
+	tl.Add("test")
+	tl.p.draw()
+	build.Done(nil)
+	tl.Stop()
+	want := "\r\x1b[Kbuild: running /\n\r\x1b[Ktest: running /\n" +
+		"\x1b[2A\r\x1b[Kbuild: done\n\r\x1b[Ktest: running -\n"
+	if got := stderr.String(); got != want {
+		t.Errorf("got output %q, wanted %q", got, want)
+	}
+}
+
+func TestProgressContextCanceled(t *testing.T) {
+	defer slowProgress()()
+	var stderr bytes.Buffer
+	ctx, cancel := context.WithCancel(context.Background())
+	pb := NewProgressBar(progressContext(ctx, &stderr), "Uploading", 10)
+	pb.Add(5)
+	cancel()
+	<-pb.p.stopped
+	pb.Add(5)
+	pb.Done()
+	want := "Uploading: 0% (0/10)\nUploading: 50% (5/10)\n"
+	if got := stderr.String(); got != want {
+		t.Errorf("got output %q, wanted %q", got, want)
+	}
+}
+
+func TestProgressTicker(t *testing.T) {
+	interval := progressLogInterval
+	progressLogInterval = time.Millisecond
+	defer func() {
+		progressLogInterval = interval
+	}()
+	var stderr bytes.Buffer
+	pb := NewProgressBar(progressContext(context.Background(), &stderr), "Copying", 2)
+	pb.Add(1)
+	time.Sleep(50 * time.Millisecond)
+	pb.Done()
+	want := "Copying: 0% (0/2)\nCopying: 50% (1/2)\n"
+	if got := stderr.String(); got != want {
+		t.Errorf("got output %q, wanted %q", got, want)
+	}
+}
@@ -0,0 +1,211 @@
+package clino
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrNonInteractive is returned by prompts when they can't ask the user for input,
+// because the input isn't a terminal or the -no-input flag is set.
+var ErrNonInteractive = errors.New("cannot prompt for input in non-interactive mode")
+
+// ErrNoOptions is returned by Select and MultiSelect when called without options.
+var ErrNoOptions = errors.New("no options to choose from")
+
+// promptSettings set by the -yes and -no-input flags.
+type promptSettings struct {
+	yes     bool
+	noInput bool
+}
+
+// interactive returns an error if the program cannot prompt the user for input.
+func interactive(ctx context.Context, question string) error {
+	settings, flags := ctx.Value(promptKey).(*promptSettings)
+	if flags && settings.noInput {
+		return fmt.Errorf("%w (-no-input is set): %s", ErrNonInteractive, question)
+	}
+	if !IO(ctx).IsInputTerminal() {
+		return fmt.Errorf("%w (input is not a terminal): %s", ErrNonInteractive, question)
+	}
+	return nil
+}
+
+// Confirm asks the user a yes or no question, returning the default answer if the user just presses enter.
+//
+// It returns true without asking if the -yes flag is set (see Program.PromptFlags).
+// 	ok, err := clino.Confirm(ctx, "Delete all files?", false)
+func Confirm(ctx context.Context, question string, defaultYes bool) (bool, error) {
+	if settings, ok := ctx.Value(promptKey).(*promptSettings); ok && settings.yes {
+		return true, nil
+	}
+	if err := interactive(ctx, question); err != nil {
+		var hint string
+		if _, ok := ctx.Value(promptKey).(*promptSettings); ok {
+			hint = "Use the -yes flag to confirm without prompting."
+		}
+		return false, DetailedError{Detail: hint, Err: err}
+	}
+	choices := "y/N"
+	if defaultYes {
+		choices = "Y/n"
+	}
+	streams := IO(ctx)
+	for {
+		fmt.Fprintf(streams.Err, "%s [%s]: ", question, choices)
+		answer, err := readLine(streams.In)
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return defaultYes, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+	}
+}
+
+// Ask the user for a text input, returning the default value if the user just presses enter.
+func Ask(ctx context.Context, question, defaultValue string) (string, error) {
+	if err := interactive(ctx, question); err != nil {
+		return "", err
+	}
+	streams := IO(ctx)
+	if defaultValue != "" {
+		fmt.Fprintf(streams.Err, "%s [%s]: ", question, defaultValue)
+	} else {
+		fmt.Fprintf(streams.Err, "%s: ", question)
+	}
+	answer, err := readLine(streams.In)
+	if answer == "" {
+		answer = defaultValue
+	}
+	return answer, err
+}
+
+// Password asks the user for a secret, without echoing it on the terminal.
+func Password(ctx context.Context, question string) (string, error) {
+	if err := interactive(ctx, question); err != nil {
+		return "", err
+	}
+	streams := IO(ctx)
+	fmt.Fprintf(streams.Err, "%s: ", question)
+	if f, ok := streams.In.(*os.File); ok {
+		restore, err := disableEcho(f.Fd())
+		if err != nil {
+			return "", fmt.Errorf("cannot disable echo: %w", err)
+		}
+		defer restore()
+	}
+	answer, err := readLine(streams.In)
+	fmt.Fprintln(streams.Err) // the newline typed by the user isn't echoed
+	return answer, err
+}
+
+// Select asks the user to choose one of the options, returning its index.
+// It fails with ErrNoOptions if there are no options.
+func Select(ctx context.Context, question string, options []string) (int, error) {
+	if len(options) == 0 {
+		return -1, fmt.Errorf("%w: %s", ErrNoOptions, question)
+	}
+	if err := interactive(ctx, question); err != nil {
+		return -1, err
+	}
+	streams := IO(ctx)
+	printOptions(streams.Err, question, options)
+	for {
+		fmt.Fprintf(streams.Err, "Enter a number [1-%d]: ", len(options))
+		answer, err := readLine(streams.In)
+		if err != nil {
+			return -1, err
+		}
+		if i, ok := parseOption(answer, len(options)); ok {
+			return i, nil
+		}
+	}
+}
+
+// MultiSelect asks the user to choose any number of the options, returning their indexes.
+// It fails with ErrNoOptions if there are no options.
+func MultiSelect(ctx context.Context, question string, options []string) ([]int, error) {
+	if len(options) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoOptions, question)
+	}
+	if err := interactive(ctx, question); err != nil {
+		return nil, err
+	}
+	streams := IO(ctx)
+	printOptions(streams.Err, question, options)
+	for {
+		fmt.Fprintf(streams.Err, "Enter numbers separated by commas [1-%d]: ", len(options))
+		answer, err := readLine(streams.In)
+		if err != nil {
+			return nil, err
+		}
+		if selected, ok := parseOptions(answer, len(options)); ok {
+			return selected, nil
+		}
+	}
+}
+
+func printOptions(w io.Writer, question string, options []string) {
+	fmt.Fprintln(w, question)
+	for i, o := range options {
+		fmt.Fprintf(w, "  %d) %s\n", i+1, o)
+	}
+}
+
+// parseOption parses a one-based option number, returning its zero-based index.
+func parseOption(answer string, n int) (int, bool) {
+	i, err := strconv.Atoi(strings.TrimSpace(answer))
+	if err != nil || i < 1 || i > n {
+		return -1, false
+	}
+	return i - 1, true
+}
+
+// parseOptions parses a list of one-based option numbers separated by commas or spaces.
+func parseOptions(answer string, n int) ([]int, bool) {
+	selected := []int{}
+	seen := map[int]bool{}
+	for _, f := range strings.FieldsFunc(answer, func(r rune) bool { return r == ',' || r == ' ' }) {
+		i, ok := parseOption(f, n)
+		if !ok {
+			return nil, false
+		}
+		if !seen[i] {
+			seen[i] = true
+			selected = append(selected, i)
+		}
+	}
+	return selected, true
+}
+
+// readLine reads a line from the reader, one byte at a time, so nothing after the line is consumed.
+func readLine(r io.Reader) (string, error) {
+	var line []byte
+	b := make([]byte, 1)
+	for {
+		n, err := r.Read(b)
+		if n == 1 {
+			if b[0] == '\n' {
+				break
+			}
+			line = append(line, b[0])
+		}
+		if err == io.EOF && len(line) != 0 {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return strings.TrimSuffix(string(line), "\r"), nil
+}
@@ -0,0 +1,252 @@
+package clino
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// promptContext returns a context with a terminal input for testing the prompts.
+func promptContext(input string, stderr io.Writer) context.Context {
+	return context.WithValue(context.Background(), ioKey, IOStreams{
+		In:  fakeTerminalInput{strings.NewReader(input)},
+		Out: ioutil.Discard,
+		Err: stderr,
+	})
+}
+
+func TestConfirm(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		input      string
+		defaultYes bool
+		want       bool
+		prompt     string
+		err        error
+	}{
+		{
+			desc:   "yes",
+			input:  "y\n",
+			want:   true,
+			prompt: "Continue? [y/N]: ",
+		},
+		{
+			desc:       "no",
+			input:      "No\r\n",
+			defaultYes: true,
+			want:       false,
+			prompt:     "Continue? [Y/n]: ",
+		},
+		{
+			desc:       "default",
+			input:      "\n",
+			defaultYes: true,
+			want:       true,
+			prompt:     "Continue? [Y/n]: ",
+		},
+		{
+			desc:   "invalid answer",
+			input:  "maybe\nyes",
+			want:   true,
+			prompt: "Continue? [y/N]: Continue? [y/N]: ",
+		},
+		{
+			desc:   "no answer",
+			input:  "",
+			prompt: "Continue? [y/N]: ",
+			err:    io.EOF,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var stderr bytes.Buffer
+			got, err := Confirm(promptContext(tc.input, &stderr), "Continue?", tc.defaultYes)
+			if err != tc.err {
+				t.Errorf("wanted error to be %v, got %v instead", tc.err, err)
+			}
+			if got != tc.want {
+				t.Errorf("wanted answer to be %v, got %v instead", tc.want, got)
+			}
+			if stderr.String() != tc.prompt {
+				t.Errorf("wanted prompt %q, got %q instead", tc.prompt, stderr.String())
+			}
+		})
+	}
+}
+
+func TestProgramConfirm(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		input       io.Reader
+		promptFlags bool
+		args        []string
+		want        bool
+		err         string
+		hint        string
+	}{
+		{
+			desc:        "yes flag",
+			input:       strings.NewReader(""),
+			promptFlags: true,
+			args:        []string{"-yes"},
+			want:        true,
+		},
+		{
+			desc:        "terminal",
+			input:       fakeTerminalInput{strings.NewReader("yes\n")},
+			promptFlags: true,
+			want:        true,
+		},
+		{
+			desc:        "no-input flag",
+			input:       fakeTerminalInput{strings.NewReader("yes\n")},
+			promptFlags: true,
+			args:        []string{"-no-input"},
+			err:         "cannot prompt for input in non-interactive mode (-no-input is set): Delete all files?",
+			hint:        "Use the -yes flag to confirm without prompting.",
+		},
+		{
+			desc:  "not a terminal",
+			input: strings.NewReader("yes\n"),
+			err:   "cannot prompt for input in non-interactive mode (input is not a terminal): Delete all files?",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			cc := &confirmCommand{}
+			p := Program{
+				Root:        cc,
+				Input:       tc.input,
+				Output:      ioutil.Discard,
+				ErrOutput:   ioutil.Discard,
+				PromptFlags: tc.promptFlags,
+			}
+			err := p.Run(context.Background(), tc.args...)
+			if (err == nil && tc.err != "") || (err != nil && err.Error() != tc.err) {
+				t.Errorf("wanted error to be %v, got %v instead", tc.err, err)
+			}
+			if err != nil && !errors.Is(err, ErrNonInteractive) {
+				t.Errorf("wanted error to wrap ErrNonInteractive, got %v instead", err)
+			}
+			if hint := errorHint(err); hint != tc.hint {
+				t.Errorf("wanted hint %q, got %q instead", tc.hint, hint)
+			}
+			if cc.confirmed != tc.want {
+				t.Errorf("wanted confirmation to be %v, got %v instead", tc.want, cc.confirmed)
+			}
+		})
+	}
+}
+
+func TestAsk(t *testing.T) {
+	var stderr bytes.Buffer
+	ctx := promptContext("\nGopher\n", &stderr)
+	if got, err := Ask(ctx, "Name", "World"); got != "World" || err != nil {
+		t.Errorf("wanted (World, <nil>), got (%v, %v) instead", got, err)
+	}
+	if got, err := Ask(ctx, "Name", ""); got != "Gopher" || err != nil {
+		t.Errorf("wanted (Gopher, <nil>), got (%v, %v) instead", got, err)
+	}
+	if want := "Name [World]: Name: "; stderr.String() != want {
+		t.Errorf("wanted prompt %q, got %q instead", want, stderr.String())
+	}
+	ctx = context.WithValue(context.Background(), ioKey, IOStreams{In: strings.NewReader("Gopher\n")})
+	if _, err := Ask(ctx, "Name", ""); !errors.Is(err, ErrNonInteractive) {
+		t.Errorf("wanted error to wrap ErrNonInteractive, got %v instead", err)
+	}
+}
+
+func TestPassword(t *testing.T) {
+	var stderr bytes.Buffer
+	got, err := Password(promptContext("s3cr3t\n", &stderr), "Password")
+	if got != "s3cr3t" || err != nil {
+		t.Errorf("wanted (s3cr3t, <nil>), got (%v, %v) instead", got, err)
+	}
+	if want := "Password: \n"; stderr.String() != want {
+		t.Errorf("wanted prompt %q, got %q instead", want, stderr.String())
+	}
+}
+
+func TestSelect(t *testing.T) {
+	var stderr bytes.Buffer
+	got, err := Select(promptContext("0\nabc\n2\n", &stderr), "Choose a planet:", []string{"Earth", "Mars"})
+	if got != 1 || err != nil {
+		t.Errorf("wanted (1, <nil>), got (%v, %v) instead", got, err)
+	}
+	want := `Choose a planet:
+  1) Earth
+  2) Mars
+Enter a number [1-2]: Enter a number [1-2]: Enter a number [1-2]: `
+	if stderr.String() != want {
+		t.Errorf("wanted prompt %q, got %q instead", want, stderr.String())
+	}
+}
+
+func TestMultiSelect(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		input string
+		want  []int
+		err   error
+	}{
+		{
+			desc:  "multiple",
+			input: "3, 1,3\n",
+			want:  []int{2, 0},
+		},
+		{
+			desc:  "none",
+			input: "\n",
+			want:  []int{},
+		},
+		{
+			desc:  "invalid answer",
+			input: "1,4\n2 1",
+			want:  []int{1, 0},
+		},
+		{
+			desc:  "no answer",
+			input: "",
+			err:   io.EOF,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := MultiSelect(promptContext(tc.input, ioutil.Discard), "Choose planets:", []string{"Earth", "Mars", "Venus"})
+			if err != tc.err {
+				t.Errorf("wanted error to be %v, got %v instead", tc.err, err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("wanted selection %v, got %v instead", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestSelectNoOptions(t *testing.T) {
+	var stderr bytes.Buffer
+	in := strings.NewReader("1\n")
+	ctx := context.WithValue(context.Background(), ioKey, IOStreams{
+		In:  fakeTerminalInput{in},
+		Out: ioutil.Discard,
+		Err: &stderr,
+	})
+	const want = "no options to choose from: Choose a planet:"
+	if got, err := Select(ctx, "Choose a planet:", nil); got != -1 || !errors.Is(err, ErrNoOptions) || err.Error() != want {
+		t.Errorf("wanted (-1, %q), got (%v, %v) instead", want, got, err)
+	}
+	if got, err := MultiSelect(ctx, "Choose a planet:", []string{}); got != nil || !errors.Is(err, ErrNoOptions) || err.Error() != want {
+		t.Errorf("wanted (nil, %q), got (%v, %v) instead", want, got, err)
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("wanted no prompt, got %q instead", stderr.String())
+	}
+	if in.Len() != 2 {
+		t.Error("wanted input not to be read")
+	}
+}
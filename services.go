@@ -0,0 +1,183 @@
+package clino
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// Provide registers a provider of a service that commands can get with Resolve, such as an HTTP client, logger, or configuration.
+//
+// The provider must be a function with the signature func(ctx context.Context) (T, error), where T is the type of the service.
+// It is called once per Run, the first time the service is resolved.
+// Providers can resolve other services using the context they receive.
+// If the service implements io.Closer, it is closed after the command runs,
+// in the reverse order services were created.
+// 	p.Provide(func(ctx context.Context) (*http.Client, error) {
+// 		return &http.Client{Timeout: 10 * time.Second}, nil
+// 	})
+//
+// Provide panics if the provider signature is invalid, or if a provider for the same type was already registered.
+func (p *Program) Provide(provider interface{}) {
+	fn := reflect.ValueOf(provider)
+	t := fn.Type()
+	if t.Kind() != reflect.Func || t.NumIn() != 1 || t.In(0) != contextType ||
+		t.NumOut() != 2 || t.Out(1) != errorType {
+		panic(fmt.Sprintf("invalid provider %v: must be func(context.Context) (T, error)", t))
+	}
+	typ := t.Out(0)
+	if _, ok := p.providers[typ]; ok {
+		panic(fmt.Sprintf("provider for %v registered multiple times", typ))
+	}
+	if p.providers == nil {
+		p.providers = map[reflect.Type]reflect.Value{}
+	}
+	p.providers[typ] = fn
+}
+
+// Resolve the service with the type target points to, setting target to it.
+// Services are registered with Program.Provide.
+// 	var client *http.Client
+// 	if err := clino.Resolve(ctx, &client); err != nil {
+// 		return err
+// 	}
+//
+// It returns an ExitSoftware error if there is no provider for the service, or if services depend on each other in a cycle.
+// Errors returned by providers are wrapped.
+func Resolve(ctx context.Context, target interface{}) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return SoftwareError(fmt.Errorf("cannot resolve service into %T: must be a non-nil pointer", target))
+	}
+	typ := v.Elem().Type()
+	s, ok := ctx.Value(servicesKey).(*services)
+	if !ok {
+		return SoftwareError(fmt.Errorf("cannot resolve service %v: not running on a program", typ))
+	}
+	service, err := s.resolve(ctx, typ)
+	if err != nil {
+		return err
+	}
+	v.Elem().Set(service)
+	return nil
+}
+
+// services created for a run.
+type services struct {
+	providers map[reflect.Type]reflect.Value
+
+	mu      sync.Mutex
+	entries map[reflect.Type]*serviceEntry
+	closers []io.Closer
+}
+
+// serviceEntry is a service that is or is being created.
+type serviceEntry struct {
+	mu      sync.Mutex
+	value   reflect.Value
+	created bool
+}
+
+func newServices(providers map[reflect.Type]reflect.Value) *services {
+	return &services{
+		providers: providers,
+		entries:   map[reflect.Type]*serviceEntry{},
+	}
+}
+
+// resolve the service of the given type, creating it if needed.
+// The context contains the services being resolved, to detect cycles.
+func (s *services) resolve(ctx context.Context, typ reflect.Type) (reflect.Value, error) {
+	provider, ok := s.providers[typ]
+	if !ok {
+		return reflect.Value{}, SoftwareError(fmt.Errorf("no provider for service %v", typ))
+	}
+	resolving, _ := ctx.Value(resolvingKey).([]reflect.Type)
+	for i, r := range resolving {
+		if r == typ {
+			return reflect.Value{}, SoftwareError(fmt.Errorf("dependency cycle: %s", dependencyCycle(resolving[i:], typ)))
+		}
+	}
+
+	s.mu.Lock()
+	e, ok := s.entries[typ]
+	if !ok {
+		e = &serviceEntry{}
+		s.entries[typ] = e
+	}
+	s.mu.Unlock()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.created {
+		return e.value, nil
+	}
+	ctx = context.WithValue(ctx, resolvingKey, append(resolving[:len(resolving):len(resolving)], typ))
+	out := provider.Call([]reflect.Value{reflect.ValueOf(ctx)})
+	if err, _ := out[1].Interface().(error); err != nil {
+		var se serviceError
+		if errors.As(err, &se) {
+			return reflect.Value{}, err // failed creating a dependency.
+		}
+		return reflect.Value{}, serviceError{typ: typ, err: err}
+	}
+	e.value, e.created = out[0], true
+	if c, ok := e.value.Interface().(io.Closer); ok && !isNil(e.value) {
+		s.mu.Lock()
+		s.closers = append(s.closers, c)
+		s.mu.Unlock()
+	}
+	return e.value, nil
+}
+
+// close the services in the reverse order they were created, returning the first error if err is nil.
+func (s *services) close(err error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := len(s.closers) - 1; i >= 0; i-- {
+		if cerr := s.closers[i].Close(); err == nil && cerr != nil {
+			err = fmt.Errorf("cannot close service: %w", cerr)
+		}
+	}
+	s.closers = nil
+	return err
+}
+
+// serviceError is returned when a provider fails.
+type serviceError struct {
+	typ reflect.Type
+	err error
+}
+
+func (se serviceError) Error() string {
+	return fmt.Sprintf("cannot create service %v: %v", se.typ, se.err)
+}
+
+func (se serviceError) Unwrap() error { return se.err }
+
+// dependencyCycle describes the cycle of services, such as "*Config -> *Client -> *Config".
+func dependencyCycle(cycle []reflect.Type, typ reflect.Type) string {
+	var names []string
+	for _, t := range cycle {
+		names = append(names, t.String())
+	}
+	return strings.Join(append(names, typ.String()), " -> ")
+}
+
+// isNil reports whether the value is a nil pointer, interface, map, slice, channel, or function.
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func:
+		return v.IsNil()
+	}
+	return false
+}
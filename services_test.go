@@ -0,0 +1,198 @@
+package clino
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type (
+	testConfig struct{ name string }
+	testClient struct{ config *testConfig }
+	testCycleA struct{}
+	testCycleB struct{}
+)
+
+// testCloser records when it is closed.
+type testCloser struct {
+	name   string
+	closed *[]string
+	err    error
+}
+
+func (tc *testCloser) Close() error {
+	*tc.closed = append(*tc.closed, tc.name)
+	return tc.err
+}
+
+func TestResolve(t *testing.T) {
+	var calls int
+	var p Program
+	p.Provide(func(ctx context.Context) (*testConfig, error) {
+		calls++
+		return &testConfig{name: "prod"}, nil
+	})
+	p.Provide(func(ctx context.Context) (*testClient, error) {
+		var c *testConfig
+		if err := Resolve(ctx, &c); err != nil {
+			return nil, err
+		}
+		return &testClient{config: c}, nil
+	})
+	p.Provide(func(ctx context.Context) (testCycleA, error) {
+		return testCycleA{}, Resolve(ctx, &testCycleB{})
+	})
+	p.Provide(func(ctx context.Context) (testCycleB, error) {
+		return testCycleB{}, Resolve(ctx, &testCycleA{})
+	})
+	p.Root = &funcCommand{run: func(ctx context.Context, args ...string) error {
+		var client, again *testClient
+		if err := Resolve(ctx, &client); err != nil {
+			return err
+		}
+		if err := Resolve(ctx, &again); err != nil {
+			return err
+		}
+		if client != again {
+			t.Error("wanted service to be created only once")
+		}
+		if client.config.name != "prod" {
+			t.Errorf("wanted client to have config, got %+v instead", client.config)
+		}
+
+		err := Resolve(ctx, &testCycleA{})
+		const want = "cannot create service clino.testCycleB: dependency cycle: clino.testCycleA -> clino.testCycleB -> clino.testCycleA"
+		if err == nil || err.Error() != want {
+			t.Errorf("wanted error %q, got %v instead", want, err)
+		}
+		if code := ExitCode(err); code != ExitSoftware {
+			t.Errorf("wanted exit code %d, got %d instead", ExitSoftware, code)
+		}
+
+		var missing *testCloser
+		err = Resolve(ctx, &missing)
+		if want := "no provider for service *clino.testCloser"; err == nil || err.Error() != want {
+			t.Errorf("wanted error %q, got %v instead", want, err)
+		}
+		return nil
+	}}
+	if err := p.Run(context.Background()); err != nil {
+		t.Errorf("wanted error to be nil, got %v instead", err)
+	}
+	if calls != 1 {
+		t.Errorf("wanted config provider to be called once, got %d calls instead", calls)
+	}
+
+	// services are created again on each run.
+	if err := p.Run(context.Background()); err != nil {
+		t.Errorf("wanted error to be nil, got %v instead", err)
+	}
+	if calls != 2 {
+		t.Errorf("wanted config provider to be called once per run, got %d calls instead", calls)
+	}
+}
+
+func TestResolveProviderError(t *testing.T) {
+	var p Program
+	p.Provide(func(ctx context.Context) (*testConfig, error) {
+		return nil, ConfigError(errors.New("missing API key"))
+	})
+	p.Root = &funcCommand{run: func(ctx context.Context, args ...string) error {
+		var c *testConfig
+		return Resolve(ctx, &c)
+	}}
+	err := p.Run(context.Background())
+	if want := "cannot create service *clino.testConfig: missing API key"; err == nil || err.Error() != want {
+		t.Errorf("wanted error %q, got %v instead", want, err)
+	}
+	if code := ExitCode(err); code != ExitConfig {
+		t.Errorf("wanted exit code %d, got %d instead", ExitConfig, code)
+	}
+}
+
+func TestResolveOutsideProgram(t *testing.T) {
+	var c *testConfig
+	err := Resolve(context.Background(), &c)
+	if want := "cannot resolve service *clino.testConfig: not running on a program"; err == nil || err.Error() != want {
+		t.Errorf("wanted error %q, got %v instead", want, err)
+	}
+	err = Resolve(context.Background(), c)
+	if want := "cannot resolve service into *clino.testConfig: must be a non-nil pointer"; err == nil || err.Error() != want {
+		t.Errorf("wanted error %q, got %v instead", want, err)
+	}
+}
+
+func TestServicesClose(t *testing.T) {
+	var closed []string
+	var p Program
+	p.Provide(func(ctx context.Context) (*testCloser, error) {
+		return &testCloser{name: "first", closed: &closed, err: errors.New("already closed")}, nil
+	})
+	p.Provide(func(ctx context.Context) (*testConfig, error) {
+		return &testConfig{}, nil
+	})
+	p.Provide(func(ctx context.Context) (testCloser, error) {
+		return testCloser{}, nil
+	})
+	type second struct{ *testCloser }
+	p.Provide(func(ctx context.Context) (second, error) {
+		return second{&testCloser{name: "second", closed: &closed}}, nil
+	})
+	p.Root = &funcCommand{run: func(ctx context.Context, args ...string) error {
+		if err := Resolve(ctx, &second{}); err != nil {
+			return err
+		}
+		var c *testCloser
+		if err := Resolve(ctx, &c); err != nil {
+			return err
+		}
+		if len(closed) != 0 {
+			t.Error("wanted services to be closed only after the command runs")
+		}
+		return nil
+	}}
+	err := p.Run(context.Background())
+	if want := "cannot close service: already closed"; err == nil || err.Error() != want {
+		t.Errorf("wanted error %q, got %v instead", want, err)
+	}
+	if want := []string{"first", "second"}; !reflect.DeepEqual(closed, want) {
+		t.Errorf("wanted services to be closed in order %q, got %q instead", want, closed)
+	}
+}
+
+func TestProvidePanics(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		provider interface{}
+		want     string
+	}{
+		{
+			desc:     "not a function",
+			provider: &testConfig{},
+			want:     "invalid provider *clino.testConfig: must be func(context.Context) (T, error)",
+		},
+		{
+			desc:     "missing error",
+			provider: func(ctx context.Context) *testConfig { return nil },
+			want:     "invalid provider func(context.Context) *clino.testConfig: must be func(context.Context) (T, error)",
+		},
+		{
+			desc:     "duplicated",
+			provider: func(ctx context.Context) (*testClient, error) { return nil, nil },
+			want:     "provider for *clino.testClient registered multiple times",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var p Program
+			p.Provide(func(ctx context.Context) (*testClient, error) { return nil, nil })
+			defer func() {
+				if r := recover(); r != tc.want {
+					t.Errorf("wanted panic %q, got %v instead", tc.want, r)
+				}
+			}()
+			p.Provide(tc.provider)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package clino
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// signalNotify and exit are replaced during tests.
+var (
+	signalNotify = signal.Notify
+	exit         = os.Exit
+)
+
+// notifySignals returns a copy of the parent context that is canceled on the first SIGINT or SIGTERM signal.
+// A second signal exits the process immediately.
+//
+// The returned stop function must be called with the error returned by the command once it finishes.
+// If a signal was received, it returns an ExitError with the conventional 128+signal exit code.
+func notifySignals(parent context.Context) (ctx context.Context, stop func(err error) error) {
+	ctx, cancel := context.WithCancel(parent)
+	c := make(chan os.Signal, 2)
+	received := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signalNotify(c, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case sig := <-c:
+			received <- sig
+			cancel()
+		case <-done:
+			return
+		}
+		select {
+		case sig := <-c:
+			exit(signalExitCode(sig))
+		case <-done:
+		}
+	}()
+	return ctx, func(err error) error {
+		signal.Stop(c)
+		close(done)
+		cancel()
+		select {
+		case sig := <-received:
+			if err == nil {
+				err = fmt.Errorf("signal: %v", sig)
+			}
+			return ExitError{
+				Code: signalExitCode(sig),
+				Err:  err,
+			}
+		default:
+			return err
+		}
+	}
+}
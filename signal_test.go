@@ -0,0 +1,104 @@
+package clino
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"syscall"
+	"testing"
+)
+
+// fakeSignals replaces the signal notification and exit functions until the returned restore function is called.
+func fakeSignals() (signals chan chan<- os.Signal, exits chan int, restore func()) {
+	signals = make(chan chan<- os.Signal, 1)
+	exits = make(chan int, 1)
+	signalNotify = func(c chan<- os.Signal, sig ...os.Signal) {
+		signals <- c
+	}
+	exit = func(code int) {
+		exits <- code
+	}
+	return signals, exits, func() {
+		signalNotify, exit = signalNotifyOriginal, exitOriginal
+	}
+}
+
+var (
+	signalNotifyOriginal = signalNotify
+	exitOriginal         = exit
+)
+
+func TestProgramHandleSignals(t *testing.T) {
+	signals, exits, restore := fakeSignals()
+	defer restore()
+
+	wc := &waitCommand{
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	p := Program{
+		Root:          wc,
+		Output:        ioutil.Discard,
+		HandleSignals: true,
+	}
+	var code int
+	go func() {
+		c := <-signals
+		<-wc.started
+		c <- syscall.SIGTERM
+		c <- os.Interrupt
+		code = <-exits
+		close(wc.release)
+	}()
+	err := p.Run(context.Background())
+	if want := 128 + int(syscall.SIGTERM); ExitCode(err) != want {
+		t.Errorf("wanted exit code %d, got %d instead", want, ExitCode(err))
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("wanted error to wrap context.Canceled, got %v instead", err)
+	}
+	if code != 128+int(syscall.SIGINT) {
+		t.Errorf("wanted second signal to exit with code %d, got %d instead", 128+int(syscall.SIGINT), code)
+	}
+}
+
+func TestProgramHandleSignalsNoSignal(t *testing.T) {
+	_, exits, restore := fakeSignals()
+	defer restore()
+
+	sc := &simpleCommand{}
+	p := Program{
+		Root:          sc,
+		Output:        ioutil.Discard,
+		HandleSignals: true,
+	}
+	if err := p.Run(context.Background()); err != nil {
+		t.Errorf("wanted error to be nil, got %v instead", err)
+	}
+	if !sc.ran {
+		t.Error("run function wasn't called")
+	}
+	select {
+	case code := <-exits:
+		t.Errorf("expected process not to exit, got exit code %d", code)
+	default:
+	}
+}
+
+func TestNotifySignalsNilError(t *testing.T) {
+	signals, _, restore := fakeSignals()
+	defer restore()
+
+	ctx, stop := notifySignals(context.Background())
+	c := <-signals
+	c <- os.Interrupt
+	<-ctx.Done()
+	err := stop(nil)
+	if want := "signal: interrupt"; err == nil || err.Error() != want {
+		t.Errorf("wanted error to be %v, got %v instead", want, err)
+	}
+	if want := 128 + int(syscall.SIGINT); ExitCode(err) != want {
+		t.Errorf("wanted exit code %d, got %d instead", want, ExitCode(err))
+	}
+}
@@ -0,0 +1,105 @@
+package clino
+
+import (
+	"context"
+	"io"
+	"os"
+)
+
+// IOStreams of a program: its input, output, and error output.
+//
+// Use the IO function to get the streams of the program from the context passed to your commands
+// instead of using os.Stdin, os.Stdout, and os.Stderr directly, so you can test them with buffers.
+type IOStreams struct {
+	In  io.Reader
+	Out io.Writer
+	Err io.Writer
+}
+
+// IsInputTerminal reports whether the input stream is a terminal.
+func (s IOStreams) IsInputTerminal() bool {
+	return IsTerminal(s.In)
+}
+
+// IsOutputTerminal reports whether the output stream is a terminal.
+func (s IOStreams) IsOutputTerminal() bool {
+	return IsTerminal(s.Out)
+}
+
+// IsErrorTerminal reports whether the error output stream is a terminal.
+func (s IOStreams) IsErrorTerminal() bool {
+	return IsTerminal(s.Err)
+}
+
+// IO streams of the program running the command.
+//
+// If the context doesn't come from a command run by Program, the standard streams are returned.
+// 	func (hc *HelloCommand) Run(ctx context.Context, args ...string) error {
+// 		fmt.Fprintf(clino.IO(ctx).Out, "Hello, %s!\n", hc.name)
+// 		return nil
+// 	}
+func IO(ctx context.Context) IOStreams {
+	if s, ok := ctx.Value(ioKey).(IOStreams); ok {
+		return s
+	}
+	return IOStreams{
+		In:  os.Stdin,
+		Out: os.Stdout,
+		Err: os.Stderr,
+	}
+}
+
+// Terminal streams report whether they are connected to a terminal.
+//
+// You can implement it on your own streams to simulate a terminal during tests.
+type Terminal interface {
+	IsTerminal() bool
+}
+
+// IsTerminal reports whether the stream is a terminal.
+// It returns true for files connected to a terminal, or streams implementing Terminal that say so.
+func IsTerminal(stream interface{}) bool {
+	switch s := stream.(type) {
+	case Terminal:
+		return s.IsTerminal()
+	case *os.File:
+		return s != nil && isTerminalFd(s.Fd())
+	}
+	return false
+}
+
+// TerminalSizer streams report the size of their terminal.
+//
+// You can implement it on your own streams to simulate a terminal during tests.
+type TerminalSizer interface {
+	TerminalSize() (width, height int)
+}
+
+// TerminalSize returns the size of the terminal the stream is connected to.
+// It returns ok = false if the stream isn't a terminal, or if its size is unknown.
+func TerminalSize(stream interface{}) (width, height int, ok bool) {
+	if t, ok := stream.(Terminal); ok && !t.IsTerminal() {
+		return 0, 0, false
+	}
+	switch s := stream.(type) {
+	case TerminalSizer:
+		width, height = s.TerminalSize()
+		return width, height, true
+	case *os.File:
+		if s != nil && isTerminalFd(s.Fd()) {
+			return terminalSizeFd(s.Fd())
+		}
+	}
+	return 0, 0, false
+}
+
+// TerminalSize returns the size of the terminal the error output, output, or input is connected to,
+// whichever is a terminal first.
+func (s IOStreams) TerminalSize() (width, height int, ok bool) {
+	for _, stream := range []interface{}{s.Err, s.Out, s.In} {
+		if width, height, ok = TerminalSize(stream); ok {
+			return width, height, ok
+		}
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,107 @@
+package clino
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProgramIO(t *testing.T) {
+	var stdout bytes.Buffer
+	stderr := &fakeTerminal{}
+	ec := &echoCommand{}
+	p := Program{
+		Root:      ec,
+		Input:     strings.NewReader("Hello, World!\n"),
+		Output:    &stdout,
+		ErrOutput: stderr,
+	}
+	if err := p.Run(context.Background(), "a", "b"); err != nil {
+		t.Errorf("wanted error to be nil, got %v instead", err)
+	}
+	if want := "Hello, World!\n"; stdout.String() != want {
+		t.Errorf("got output %q, wanted %q", stdout.String(), want)
+	}
+	if want := "a b\n"; stderr.String() != want {
+		t.Errorf("got error output %q, wanted %q", stderr.String(), want)
+	}
+	if want := []bool{false, false, true}; !reflect.DeepEqual(ec.terminals, want) {
+		t.Errorf("wanted terminals to be %v, got %v instead", want, ec.terminals)
+	}
+}
+
+func TestIOOutsideProgram(t *testing.T) {
+	streams := IO(context.Background())
+	if streams.In != os.Stdin || streams.Out != os.Stdout || streams.Err != os.Stderr {
+		t.Errorf("expected standard streams, got %+v instead", streams)
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	f, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	testCases := []struct {
+		desc   string
+		stream interface{}
+		want   bool
+	}{
+		{
+			desc:   "nil",
+			stream: nil,
+		},
+		{
+			desc:   "buffer",
+			stream: &bytes.Buffer{},
+		},
+		{
+			desc:   "fake terminal",
+			stream: &fakeTerminal{},
+			want:   true,
+		},
+		{
+			desc:   "null device",
+			stream: f,
+		},
+		{
+			desc:   "nil file",
+			stream: (*os.File)(nil),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := IsTerminal(tc.stream); got != tc.want {
+				t.Errorf("wanted IsTerminal(%v) = %v, got %v instead", tc.stream, tc.want, got)
+			}
+		})
+	}
+}
+
+// fakeSizedTerminal is a buffer simulating a terminal with a known size.
+type fakeSizedTerminal struct {
+	fakeTerminal
+}
+
+func (fst *fakeSizedTerminal) TerminalSize() (width, height int) {
+	return 100, 40
+}
+
+func TestTerminalSize(t *testing.T) {
+	streams := IOStreams{
+		In:  strings.NewReader(""),
+		Out: &bytes.Buffer{},
+		Err: &fakeSizedTerminal{},
+	}
+	if width, height, ok := streams.TerminalSize(); width != 100 || height != 40 || !ok {
+		t.Errorf("wanted terminal size (100, 40, true), got (%v, %v, %v) instead", width, height, ok)
+	}
+	streams.Err = &fakeTerminal{}
+	if width, height, ok := streams.TerminalSize(); width != 0 || height != 0 || ok {
+		t.Errorf("wanted unknown terminal size, got (%v, %v, %v) instead", width, height, ok)
+	}
+}
@@ -0,0 +1,21 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !windows
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!windows
+
+package clino
+
+import (
+	"errors"
+	"runtime"
+)
+
+func isTerminalFd(fd uintptr) bool {
+	return false
+}
+
+func disableEcho(fd uintptr) (restore func(), err error) {
+	return nil, errors.New("not supported on " + runtime.GOOS)
+}
+
+func terminalSizeFd(fd uintptr) (width, height int, ok bool) {
+	return 0, 0, false
+}
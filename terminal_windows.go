@@ -0,0 +1,48 @@
+package clino
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+const enableEchoInput = 0x4
+
+var (
+	kernel32                   = syscall.NewLazyDLL("kernel32.dll")
+	setConsoleMode             = kernel32.NewProc("SetConsoleMode")
+	getConsoleScreenBufferInfo = kernel32.NewProc("GetConsoleScreenBufferInfo")
+)
+
+func isTerminalFd(fd uintptr) bool {
+	var mode uint32
+	return syscall.GetConsoleMode(syscall.Handle(fd), &mode) == nil
+}
+
+func disableEcho(fd uintptr) (restore func(), err error) {
+	var mode uint32
+	if err := syscall.GetConsoleMode(syscall.Handle(fd), &mode); err != nil {
+		return nil, err
+	}
+	if r, _, err := setConsoleMode.Call(fd, uintptr(mode&^enableEchoInput)); r == 0 {
+		return nil, err
+	}
+	return func() {
+		setConsoleMode.Call(fd, uintptr(mode))
+	}, nil
+}
+
+func terminalSizeFd(fd uintptr) (width, height int, ok bool) {
+	type coord struct {
+		x, y int16
+	}
+	var info struct {
+		size, cursorPosition     coord
+		attributes               uint16
+		left, top, right, bottom int16
+		maximumWindowSize        coord
+	}
+	if r, _, _ := getConsoleScreenBufferInfo.Call(fd, uintptr(unsafe.Pointer(&info))); r == 0 {
+		return 0, 0, false
+	}
+	return int(info.right-info.left) + 1, int(info.bottom-info.top) + 1, true
+}
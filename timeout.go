@@ -0,0 +1,38 @@
+package clino
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Timeouter commands have a default timeout for running.
+//
+// The context passed to the Run function is canceled once the timeout is reached.
+// A timeout of zero means no timeout.
+// Users can override it with the -timeout flag when Program.TimeoutFlag is set.
+type Timeouter interface {
+	Timeout() time.Duration
+}
+
+func commandTimeout(cmd Command) time.Duration {
+	if t, ok := cmd.(Timeouter); ok && t != nil {
+		return t.Timeout()
+	}
+	return 0
+}
+
+// runWithTimeout calls the run function with a context bounded by the timeout.
+// If the timeout is reached and the run function fails, it returns an ExitError with the given exit code.
+func runWithTimeout(ctx context.Context, timeout time.Duration, code int, run RunFunc, args []string) error {
+	tctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err := run(tctx, args...)
+	if err != nil && tctx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		return ExitError{
+			Code: code,
+			Err:  fmt.Errorf("timed out after %v: %w", timeout, err),
+		}
+	}
+	return err
+}
@@ -0,0 +1,111 @@
+package clino
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProgramTimeout(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		timeout     time.Duration
+		timeoutFlag bool
+		args        []string
+		exitCode    int
+		err         string
+		wantCode    int
+	}{
+		{
+			desc:    "default timeout",
+			timeout: 10 * time.Millisecond,
+			err:     "timed out after 10ms: context deadline exceeded",
+		},
+		{
+			desc:        "default timeout with -timeout flag",
+			timeout:     time.Hour,
+			timeoutFlag: true,
+			args:        []string{"-timeout", "5ms"},
+			err:         "timed out after 5ms: context deadline exceeded",
+		},
+		{
+			desc:        "-timeout flag",
+			timeoutFlag: true,
+			args:        []string{"-timeout=5ms"},
+			err:         "timed out after 5ms: context deadline exceeded",
+		},
+		{
+			desc:     "custom exit code",
+			timeout:  5 * time.Millisecond,
+			exitCode: 99,
+			err:      "timed out after 5ms: context deadline exceeded",
+			wantCode: 99,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			p := Program{
+				Root:                     &timeoutCommand{timeout: tc.timeout},
+				TimeoutFlag:              tc.timeoutFlag,
+				DeadlineExceededExitCode: tc.exitCode,
+			}
+			err := p.Run(context.Background(), tc.args...)
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("wanted error to be %v, got %v instead", tc.err, err)
+			}
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("wanted error to wrap context.DeadlineExceeded, got %v instead", err)
+			}
+			want := tc.wantCode
+			if want == 0 {
+				want = ExitTimeout
+			}
+			if code := ExitCode(err); code != want {
+				t.Errorf("wanted exit code %d, got %d instead", want, code)
+			}
+		})
+	}
+}
+
+func TestProgramTimeoutParentCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p := Program{
+		Root: &timeoutCommand{timeout: time.Hour},
+	}
+	if err := p.Run(ctx); err != context.Canceled {
+		t.Errorf("wanted error to be %v, got %v instead", context.Canceled, err)
+	}
+}
+
+func TestProgramTimeoutNotReached(t *testing.T) {
+	sc := &simpleCommand{}
+	p := Program{
+		Root:        sc,
+		TimeoutFlag: true,
+	}
+	if err := p.Run(context.Background(), "-timeout", "1h"); err != nil {
+		t.Errorf("wanted error to be nil, got %v instead", err)
+	}
+	if !sc.ran {
+		t.Error("run function wasn't called")
+	}
+}
+
+func TestProgramTimeoutHelp(t *testing.T) {
+	var buf bytes.Buffer
+	p := Program{
+		Root:        &timeoutCommand{timeout: time.Minute},
+		Output:      &buf,
+		TimeoutFlag: true,
+	}
+	if err := p.Run(context.Background(), "-h"); err != nil {
+		t.Errorf("wanted error to be nil, got %v instead", err)
+	}
+	if want := "maximum time for the command to run (default 1m0s)"; !strings.Contains(buf.String(), want) {
+		t.Errorf("wanted help output to contain %q, got %v instead", want, buf.String())
+	}
+}
@@ -0,0 +1,156 @@
+package clino
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// ValidationError contains all the problems found in a command tree by Validate.
+type ValidationError struct {
+	Problems []string
+}
+
+// Error returns the problems, one per line.
+func (ve *ValidationError) Error() string {
+	return strings.Join(ve.Problems, "\n")
+}
+
+// Validate the command tree, reporting all problems at once.
+//
+// It checks for:
+// duplicated command names, commands that don't implement any of the Runnable, Longer, Parent, or Footer interfaces,
+// names that are empty, contain spaces, or start with a dash,
+// subcommands without a short description (see the Shorter interface),
+// and flags defined more than once, including flags of a command redefining a persistent flag of one of its ancestors.
+//
+// Program only detects some of these problems when the offending command runs, sometimes by panicking,
+// so you probably want to call Validate with your root command in a unit test.
+func Validate(root Command) error {
+	v := &validator{}
+	v.walk(root, []string{root.Name()}, map[string]string{})
+	if len(v.problems) != 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
+
+type validator struct {
+	problems []string
+}
+
+func (v *validator) problemf(format string, a ...interface{}) {
+	v.problems = append(v.problems, fmt.Sprintf(format, a...))
+}
+
+// walk validates the command and its offspring.
+// The persistent map contains the persistent flags defined by the ancestors of the command, and their owners.
+func (v *validator) walk(cmd Command, trail []string, persistent map[string]string) {
+	name, path := cmd.Name(), strings.Join(trail, " ")
+	switch {
+	case name == "":
+		v.problemf("command '%s' has an empty name", path)
+	case strings.IndexFunc(name, unicode.IsSpace) != -1:
+		v.problemf("command '%s' has a name containing spaces", path)
+	case strings.HasPrefix(name, "-"):
+		v.problemf("command '%s' has a name starting with a dash", path)
+	}
+	if !isImplemented(cmd) {
+		v.problemf("command or topic '%s' is missing implementation", path)
+	}
+
+	inherited := map[string]string{}
+	for k, owner := range persistent {
+		inherited[k] = owner
+	}
+	if f, ok := cmd.(PersistentFlagSet); ok && f != nil {
+		v.checkFlags(path, "persistent flag", f.PersistentFlags, inherited)
+	}
+	if f, ok := cmd.(FlagSet); ok && f != nil {
+		v.checkFlags(path, "flag", f.Flags, copyFlags(inherited))
+	}
+
+	if d, ok := cmd.(DefaultCommander); ok && d != nil {
+		v.checkDefaultCommand(cmd, d.DefaultCommand(), path)
+	}
+
+	seen := map[string]bool{}
+	for _, c := range getSubcommands(cmd) {
+		ctrail := append(trail[:len(trail):len(trail)], c.Name())
+		if seen[c.Name()] {
+			v.problemf("command implemented multiple times: '%s'", strings.Join(ctrail, " "))
+			continue
+		}
+		seen[c.Name()] = true
+		if s, ok := c.(Shorter); !ok || s == nil || s.Short() == "" {
+			v.problemf("command '%s' is missing a short description", strings.Join(ctrail, " "))
+		}
+		v.walk(c, ctrail, inherited)
+	}
+}
+
+// checkDefaultCommand checks if the default command exists and is used.
+func (v *validator) checkDefaultCommand(cmd Command, name, path string) {
+	if isRunnable(cmd) {
+		v.problemf("command '%s' is runnable, so its default command is never used", path)
+	}
+	if _, ok := getCommand(getSubcommands(cmd), name); !ok {
+		v.problemf("default command '%s' of command '%s' does not exist", name, path)
+	}
+}
+
+// checkFlags defines the flags on a new flag set, and checks if any of them is already defined.
+// Defined flags are added to the defined map.
+func (v *validator) checkFlags(path, kind string, define func(*flag.FlagSet), defined map[string]string) {
+	names, err := flagNames(define)
+	if err != nil {
+		v.problemf("command '%s' cannot define its flags: %v", path, err)
+	}
+	for _, name := range names {
+		owner, ok := defined[name]
+		switch {
+		case !ok:
+			defined[name] = path
+		default:
+			v.problems = append(v.problems, flagConflict(kind, name, path, owner))
+		}
+	}
+}
+
+// flagNames returns the names of the flags defined by the function, in lexicographical order.
+func flagNames(define func(*flag.FlagSet)) (names []string, err error) {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.New(strings.TrimSpace(fmt.Sprint(r)))
+		}
+		fs.VisitAll(func(f *flag.Flag) {
+			names = append(names, f.Name)
+		})
+		sort.Strings(names)
+	}()
+	define(fs)
+	return names, nil
+}
+
+func copyFlags(m map[string]string) map[string]string {
+	c := map[string]string{}
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+// isImplemented reports whether the command implements any of the Runnable, Longer, Parent, or Footer interfaces.
+func isImplemented(cmd Command) bool {
+	switch cmd.(type) {
+	case Runnable, Longer, Parent, Footer:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package clino
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		desc string
+		root Command
+		want []string
+	}{
+		{
+			desc: "valid",
+			root: &treeCommand{
+				name:       "app",
+				persistent: []string{"verbose"},
+				children: []Command{
+					&treeCommand{name: "list", short: "list items", flags: []string{"all"}},
+					&treeCommand{name: "get", short: "get item", flags: []string{"all"}},
+				},
+			},
+		},
+		{
+			desc: "valid root command only",
+			root: &simpleCommand{},
+		},
+		{
+			desc: "problems",
+			root: &treeCommand{
+				name:       "app",
+				persistent: []string{"verbose"},
+				children: []Command{
+					&treeCommand{name: "list", short: "list items"},
+					&treeCommand{name: "list", short: "list items again"},
+					&treeCommand{name: "two words", short: "spaces"},
+					&treeCommand{name: "-dash", short: "dash"},
+					&treeCommand{name: "", short: "empty"},
+					&bareCommand{name: "bare"},
+					&treeCommand{
+						name:       "server",
+						short:      "manage servers",
+						flags:      []string{"verbose"},
+						persistent: []string{"region"},
+						children: []Command{
+							&treeCommand{name: "start", flags: []string{"region", "wait"}, persistent: []string{"wait"}},
+						},
+					},
+					&treeCommand{name: "dup", short: "duplicated flag", flags: []string{"x", "x"}},
+					&defaultParentCommand{
+						name:     "config",
+						def:      "missing",
+						children: []Command{&treeCommand{name: "show", short: "show config"}},
+					},
+				},
+			},
+			want: []string{
+				"command implemented multiple times: 'app list'",
+				"command 'app two words' has a name containing spaces",
+				"command 'app -dash' has a name starting with a dash",
+				"command 'app ' has an empty name",
+				"command 'app bare' is missing a short description",
+				"command or topic 'app bare' is missing implementation",
+				"flag -verbose of command 'app server' is already defined by command 'app'",
+				"command 'app server start' is missing a short description",
+				"flag -region of command 'app server start' is already defined by command 'app server'",
+				"flag -wait of command 'app server start' is already defined as a persistent flag",
+				"command 'app dup' cannot define its flags: flag redefined: x",
+				"default command 'missing' of command 'app config' does not exist",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := Validate(tc.root)
+			if tc.want == nil {
+				if err != nil {
+					t.Errorf("wanted error to be nil, got %v instead", err)
+				}
+				return
+			}
+			var ve *ValidationError
+			if !errors.As(err, &ve) {
+				t.Fatalf("wanted ValidationError, got %v instead", err)
+			}
+			if !reflect.DeepEqual(ve.Problems, tc.want) {
+				t.Errorf("got problems %q, wanted %q", ve.Problems, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package clino
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/debug"
+	"text/tabwriter"
+)
+
+// Version information of the program.
+//
+// When Program.Version is set, a "version" command and a -version flag are
+// added to the root command.
+// 	p := clino.Program{
+// 		Root: &RootCommand{},
+// 		Version: &clino.Version{
+// 			Version:   "1.2.3",
+// 			Commit:    commit, // set with -ldflags "-X main.commit=..."
+// 			BuildDate: date,
+// 		},
+// 	}
+type Version struct {
+	// Version of the program, preferably a semantic version string like 1.2.3.
+	// If empty, the main module version from the build information is used.
+	Version string `json:"version"`
+
+	// Commit the program was built from.
+	Commit string `json:"commit,omitempty"`
+
+	// BuildDate of the program.
+	BuildDate string `json:"build_date,omitempty"`
+
+	// GoVersion used to build the program.
+	// If empty, the version of the Go runtime is used.
+	GoVersion string `json:"go_version"`
+}
+
+// resolve fills the empty fields of the version with information read from the binary.
+func (v Version) resolve() Version {
+	if v.Version == "" {
+		if bi, ok := debug.ReadBuildInfo(); ok {
+			v.Version = bi.Main.Version
+		}
+	}
+	if v.Version == "" {
+		v.Version = "(devel)"
+	}
+	if v.GoVersion == "" {
+		v.GoVersion = runtime.Version()
+	}
+	return v
+}
+
+// print version information in plain text or JSON.
+func (v Version) print(w io.Writer, asJSON bool) error {
+	v = v.resolve()
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Version:\t%s\n", v.Version)
+	if v.Commit != "" {
+		fmt.Fprintf(tw, "Commit:\t%s\n", v.Commit)
+	}
+	if v.BuildDate != "" {
+		fmt.Fprintf(tw, "Build date:\t%s\n", v.BuildDate)
+	}
+	fmt.Fprintf(tw, "Go version:\t%s\n", v.GoVersion)
+	return tw.Flush()
+}
+
+// versionCommand is the built-in "version" command added when Program.Version is set.
+type versionCommand struct {
+	p    *Program
+	json bool
+}
+
+// Name of the version command.
+func (vc *versionCommand) Name() string {
+	return "version"
+}
+
+// Short description of the version command.
+func (vc *versionCommand) Short() string {
+	return "print version information"
+}
+
+// Flags of the version command.
+func (vc *versionCommand) Flags(flags *flag.FlagSet) {
+	flags.BoolVar(&vc.json, "json", false, "print version information in JSON format")
+}
+
+// Run version command.
+func (vc *versionCommand) Run(ctx context.Context, args ...string) error {
+	return vc.p.Version.print(vc.p.Output, vc.json)
+}
@@ -0,0 +1,162 @@
+package clino
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"io/ioutil"
+	"runtime"
+	"testing"
+)
+
+func TestProgramVersion(t *testing.T) {
+	version := &Version{
+		Version:   "1.2.3",
+		Commit:    "5b15941",
+		BuildDate: "2021-04-01T10:00:00Z",
+		GoVersion: "go1.16.3",
+	}
+	plain := `Version:     1.2.3
+Commit:      5b15941
+Build date:  2021-04-01T10:00:00Z
+Go version:  go1.16.3
+`
+	testCases := []struct {
+		desc string
+		root Command
+		args []string
+		want string
+	}{
+		{
+			desc: "version command",
+			root: &rootCommand{},
+			args: []string{"version"},
+			want: plain,
+		},
+		{
+			desc: "version command with -json",
+			root: &rootCommand{},
+			args: []string{"version", "-json"},
+			want: `{
+  "version": "1.2.3",
+  "commit": "5b15941",
+  "build_date": "2021-04-01T10:00:00Z",
+  "go_version": "go1.16.3"
+}
+`,
+		},
+		{
+			desc: "-version flag on a runnable root command",
+			root: &simpleCommand{},
+			args: []string{"-version"},
+			want: plain,
+		},
+		{
+			desc: "-version flag on a root command that is not runnable",
+			root: &rootCommand{},
+			args: []string{"-version"},
+			want: plain,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var buf bytes.Buffer
+			p := Program{
+				Root:    tc.root,
+				Output:  &buf,
+				Version: version,
+			}
+			if err := p.Run(context.Background(), tc.args...); err != nil {
+				t.Errorf("wanted error to be nil, got %v instead", err)
+			}
+			if got := buf.String(); got != tc.want {
+				t.Errorf("got output %v\n, wanted %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestProgramVersionHelp(t *testing.T) {
+	var buf bytes.Buffer
+	p := Program{
+		Root:    &rootCommand{},
+		Output:  &buf,
+		Version: &Version{},
+	}
+	if err := p.Run(context.Background(), "help"); err != nil {
+		t.Errorf("wanted error to be nil, got %v instead", err)
+	}
+	const golden = "testdata/root_help_version.golden"
+	if *update {
+		if err := ioutil.WriteFile(golden, buf.Bytes(), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bs, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("opening %s: %v", golden, err)
+	}
+	if got := buf.String(); got != string(bs) {
+		t.Errorf("got output %v\n, wanted %v", got, string(bs))
+	}
+}
+
+func TestVersionResolve(t *testing.T) {
+	v := Version{}.resolve()
+	if v.Version == "" {
+		t.Error("expected version to be resolved")
+	}
+	if v.GoVersion != runtime.Version() {
+		t.Errorf("expected Go version to be %v, got %v instead", runtime.Version(), v.GoVersion)
+	}
+}
+
+func TestProgramVersionFlagDefinedByRoot(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		root   *treeCommand
+		global bool
+	}{
+		{
+			desc: "flag",
+			root: &treeCommand{name: "app", flags: []string{"version"}},
+		},
+		{
+			desc: "persistent flag",
+			root: &treeCommand{name: "app", persistent: []string{"version"}},
+		},
+		{
+			desc:   "global flag",
+			root:   &treeCommand{name: "app"},
+			global: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var buf bytes.Buffer
+			p := Program{
+				Root:    tc.root,
+				Output:  &buf,
+				Version: &Version{Version: "1.2.3"},
+			}
+			var version *bool
+			if tc.global {
+				p.GlobalFlags = func(flags *flag.FlagSet) {
+					version = flags.Bool("version", false, "")
+				}
+			}
+			if err := p.Run(context.Background(), "-version"); err != nil {
+				t.Errorf("wanted error to be nil, got %v instead", err)
+			}
+			if !tc.global {
+				version = tc.root.values["version"]
+			}
+			if !*version {
+				t.Error("wanted -version flag of the root command to be set")
+			}
+			if buf.Len() != 0 {
+				t.Errorf("wanted no version information to be printed, got %q instead", buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package clino
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// splitWords splits the string into words like a POSIX shell does, without expanding variables or globs.
+//
+// Words are separated by spaces. Single quotes preserve the literal value of all characters between them.
+// Double quotes do too, except for the backslash, which escapes ", \, $, and `.
+// Outside quotes, a backslash preserves the literal value of the next character.
+func splitWords(s string) ([]string, error) {
+	return split(s, false)
+}
+
+// splitWordsComments splits the string into words like splitWords,
+// but a # starting a word begins a comment ignoring the rest of the line, like a shell does.
+func splitWordsComments(s string) ([]string, error) {
+	return split(s, true)
+}
+
+func split(s string, comments bool) ([]string, error) {
+	var (
+		words   []string
+		word    strings.Builder
+		inWord  bool
+		quote   rune
+		escape  bool
+		comment bool
+	)
+	for _, r := range s {
+		switch {
+		case comment:
+			comment = r != '\n'
+		case escape:
+			if quote == '"' && !strings.ContainsRune("\"\\$`", r) {
+				word.WriteRune('\\')
+			}
+			word.WriteRune(r)
+			escape = false
+		case r == quote:
+			quote = 0
+		case quote == '\'':
+			word.WriteRune(r)
+		case r == '\\':
+			escape, inWord = true, true
+		case quote == '"':
+			word.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case comments && r == '#' && !inWord:
+			comment = true
+		case unicode.IsSpace(r):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	switch {
+	case escape:
+		return nil, errors.New("unterminated escape sequence")
+	case quote != 0:
+		return nil, errors.New("unterminated quoted string")
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
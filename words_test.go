@@ -0,0 +1,55 @@
+package clino
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitWords(t *testing.T) {
+	testCases := []struct {
+		in      string
+		want    []string
+		wantErr string
+	}{
+		{in: "", want: nil},
+		{in: "   ", want: nil},
+		{in: "checkout -force", want: []string{"checkout", "-force"}},
+		{in: "  a\tb\n c  ", want: []string{"a", "b", "c"}},
+		{in: `commit -m 'fix bug'`, want: []string{"commit", "-m", "fix bug"}},
+		{in: `commit -m "say \"hi\" \n"`, want: []string{"commit", "-m", `say "hi" \n`}},
+		{in: `a\ b c\\d`, want: []string{"a b", `c\d`}},
+		{in: `'it''s' "" ''`, want: []string{"its", "", ""}},
+		{in: `'a "b"' "c 'd'"`, want: []string{`a "b"`, "c 'd'"}},
+		{in: `x'y'"z"`, want: []string{"xyz"}},
+		{in: `'open`, wantErr: "unterminated quoted string"},
+		{in: `"open`, wantErr: "unterminated quoted string"},
+		{in: `end\`, wantErr: "unterminated escape sequence"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := splitWords(tc.in)
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Errorf("wanted error %q, got %v instead", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("wanted error to be nil, got %v instead", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %q, wanted %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSplitWordsComments(t *testing.T) {
+	got, err := splitWordsComments("# comment\na b#c # rest\n'#d' \\#e\n")
+	if err != nil {
+		t.Fatalf("wanted error to be nil, got %v instead", err)
+	}
+	if want := []string{"a", "b#c", "#d", "#e"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, wanted %q", got, want)
+	}
+}